@@ -8,3 +8,7 @@ type Before struct {
 }
 
 var globalW = globalX + 42 // Reference to value spec from main
+
+func init() {
+	initOrder = append(initOrder, 1) // Runs before `init`s in 'main.gx.go'
+}
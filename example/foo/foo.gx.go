@@ -15,3 +15,24 @@ func (f *Foo) Val() int {
 func NewFoo(val int) Foo {
 	return Foo{val}
 }
+
+var Initialized bool
+
+func init() {
+	Initialized = true
+}
+
+//gx:enum
+type Mode int
+
+const (
+	ModeIdle Mode = iota
+	ModeRun
+)
+
+type Priority int // Not marked, so not an enum since this package uses `//gx:enum`
+
+const (
+	PriorityLow Priority = iota
+	PriorityHigh
+)
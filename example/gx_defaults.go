@@ -0,0 +1,43 @@
+// Code generated by gx from `default` field tags. DO NOT EDIT.
+
+//go:build !gx
+
+package main
+
+// NewJSONStats returns a new JSONStats with its `default` field tags applied
+func NewJSONStats() JSONStats {
+	return JSONStats{
+		Speed: 2.5,
+	}
+}
+
+// NewJSONEnemy returns a new JSONEnemy with its `default` field tags applied
+func NewJSONEnemy() JSONEnemy {
+	return JSONEnemy{
+		Stats: NewJSONStats(),
+	}
+}
+
+// NewSaveV2 returns a new SaveV2 with its `default` field tags applied
+func NewSaveV2() SaveV2 {
+	return SaveV2{
+		Mana: 5,
+	}
+}
+
+// NewPlayerStateV2 returns a new PlayerStateV2 with its `default` field tags applied
+func NewPlayerStateV2() PlayerStateV2 {
+	return PlayerStateV2{
+		Speed: 3,
+		Mana:  50,
+	}
+}
+
+// NewHasDefaults returns a new HasDefaults with its `default` field tags applied
+func NewHasDefaults() HasDefaults {
+	return HasDefaults{
+		foo:   42,
+		bar:   6.4,
+		point: Point{1, 2},
+	}
+}
@@ -1,116 +1,344 @@
 package main
 
-//
-// Built-ins
-//
-
-//gxsl:extern vec2
-type Vec2 struct {
-	X, Y float64
-}
-
-//gxsl:extern vec4
-type Vec4 struct {
-	X, Y, Z, W float64
-}
-
-//gxsl:extern +
-func (v Vec4) Add(u Vec4) Vec4
-
-//gxsl:extern -
-func (v Vec4) Negate() Vec4
-
-//gxsl:extern *
-func (v Vec4) Multiply(u Vec4) Vec4
-
-//gxsl:extern *
-func (v Vec4) Scale(f float64) Vec4
-
-//gxsl:extern dot
-func (v Vec4) DotProduct(u Vec4) float64
-
-//gxsl:extern sampler2D
-type Sampler2D struct{}
-
-//gxsl:extern texture2D
-func Texture2D(sampler Sampler2D, coord Vec2) Vec4
-
-//gxsl:extern gl_FragColor
-var gl_FragColor Vec4
+import "github.com/nikki93/gx/gxsl"
 
 //
 // Shader
 //
 
-//gx:extern INVALID
 type Varyings struct {
-	FragTexCoord Vec2
-	FragColor    Vec4
+	FragTexCoord gxsl.Vec2
+	FragColor    gxsl.Vec4
 }
 
-//gx:extern INVALID
 type RedTextureParams struct {
-	ColDiffuse Vec4
-	Texture0   Sampler2D
+	ColDiffuse gxsl.Vec4
+	Texture0   gxsl.Sampler2D
 	Triple     FloatTriple
 	Tricky     Varyings
 }
 
-//gx:extern INVALID
-func scaleByFive(vec Vec4) Vec4 {
+func scaleByFive(vec gxsl.Vec4) gxsl.Vec4 {
 	return scaleByNum(vec, 3).Add(scaleByTwo(vec))
 }
 
-//gx:extern INVALID
-func scaleByTwo(vec Vec4) Vec4 {
+func scaleByTwo(vec gxsl.Vec4) gxsl.Vec4 {
 	return scaleByNum(vec, 2)
 }
 
-//gx:extern INVALID
-func scaleByNum(vec Vec4, num float64) Vec4 {
+func scaleByNum(vec gxsl.Vec4, num float64) gxsl.Vec4 {
 	return vec.Scale(num)
 }
 
-//gx:extern INVALID
 type FloatPair struct {
 	A, B float64
 }
 
-//gx:extern INVALID
 func (pair FloatPair) Sum() float64 {
 	return pair.A + pair.B
 }
 
-//gx:extern INVALID
+// Fields left out of struct literals, or of zero values, get their defaults
+type Tint struct {
+	Strength float64 `default:"1"`
+	Offset   float64
+}
+
 type FloatTriple struct {
 	A, B, C float64
 }
 
-//gx:extern INVALID
-var red = Vec4{-1, -0.2, -0.2, -1}.Negate()
+var red = gxsl.Vec4{-1, -0.2, -0.2, -1}.Negate()
 
-//gxsl:shader
+//gxsl:fragment
 func redTextureShader(uniforms RedTextureParams, varyings Varyings) {
 	result := red
 
-	texelColor := Texture2D(uniforms.Texture0, varyings.FragTexCoord)
-	result = result.Multiply(texelColor)
+	texelColor := gxsl.Texture2D(uniforms.Texture0, varyings.FragTexCoord)
+	result = result.Mul(texelColor)
 
-	result = result.Multiply(uniforms.ColDiffuse)
+	result = result.Mul(uniforms.ColDiffuse)
 
-	result = result.Multiply(varyings.FragColor)
+	result = result.Mul(varyings.FragColor)
 
-	result = scaleByFive(result.Scale(result.DotProduct(Vec4{1, 0, 0, 1})))
+	result = scaleByFive(result.Scale(gxsl.Dot(result, gxsl.Vec4{1, 0, 0, 1})))
 
 	floatPair := FloatPair{2, 3}
 	result = result.Scale(floatPair.Sum())
 
-	gl_FragColor = result
+	tint := Tint{Offset: 0}
+	var zeroTint Tint
+	result = result.Scale(tint.Strength*zeroTint.Strength + zeroTint.Offset)
+	var zeroColor gxsl.Vec4
+	result = result.Add(zeroColor)
+
+	gxsl.FragColor = result
+}
+
+type SpriteAttributes struct {
+	VertexPosition gxsl.Vec2
+	VertexTexCoord gxsl.Vec2
+	VertexColor    gxsl.Vec4
+}
+
+type SpriteParams struct {
+	Model  gxsl.Mat4
+	Offset gxsl.Vec4
+}
+
+//gxsl:vertex
+func spriteShader(uniforms SpriteParams, attributes SpriteAttributes, varyings *Varyings) {
+	varyings.FragTexCoord = attributes.VertexTexCoord
+	varyings.FragColor = attributes.VertexColor
+	position := gxsl.Vec4FromVec3(gxsl.Vec3FromVec2(attributes.VertexPosition, 0), 1)
+	gxsl.Position = uniforms.Model.Transform(position).Add(uniforms.Offset)
+}
+
+//gxsl:program spriteShader redTextureShader
+
+type SplitOutputs struct {
+	Color    gxsl.Vec4
+	TexCoord gxsl.Vec4
+}
+
+//gxsl:fragment
+//gxsl:profile 330
+func splitShader(varyings Varyings, outputs *SplitOutputs) {
+	outputs.Color = varyings.FragColor
+	steps := 0
+	for i := 0; i < 4; i++ {
+		steps += i
+	}
+	outputs.TexCoord = gxsl.Vec4{varyings.FragTexCoord.X, varyings.FragTexCoord.Y, float64(steps >> 1), float64(uint(steps) & 1)}
+}
+
+// Variant parameters of `toneShader`
+const (
+	Fog       = false
+	ToneSteps = 4
+)
+
+var fogColor = gxsl.Vec3{0.5, 0.6, 0.7}
+
+func fogAmount(depth float64) float64 {
+	return gxsl.Clamp(depth*depth, 0, 1)
+}
+
+//gxsl:fragment
+//gxsl:variant Fog=false,true
+//gxsl:variant ToneSteps=4,2
+func toneShader(varyings Varyings) {
+	color := varyings.FragColor.XYZ()
+	weight := 0.0
+	for i := 0; i < ToneSteps; i++ {
+		weight += 1.0 / ToneSteps
+	}
+	gray := gxsl.Vec3{1, 1, 1}.Scale(gxsl.Dot(color, gxsl.Vec3{0.25, 0.5, 0.25}) * weight)
+	toned := gxsl.Mix(color, gray, gxsl.Smoothstep(0.0, 1.0, varyings.FragTexCoord.X))
+	if Fog {
+		toned = gxsl.Mix(toned, fogColor, fogAmount(varyings.FragTexCoord.Y))
+	}
+	gxsl.FragColor = gxsl.Vec4FromVec3(gxsl.Clamp(toned, gxsl.Vec3{0, 0, 0}, gxsl.Vec3{1, 1, 1}), varyings.FragColor.W)
+}
+
+// Samples a sky box in the direction of each fragment, undoing the view rotation
+type SkyParams struct {
+	Sky      gxsl.SamplerCube
+	Rotation gxsl.Mat3
+}
+
+//gxsl:fragment
+//gxsl:profile 300es
+func skyShader(uniforms SkyParams, varyings Varyings) {
+	ndc := gxsl.ModScalar(varyings.FragTexCoord, 1).Scale(2).Sub(gxsl.Vec2{1, 1})
+	dir := gxsl.Inverse(uniforms.Rotation).Transform(gxsl.Vec3FromVec2(ndc, 1))
+	color := gxsl.TextureCube(uniforms.Sky, dir).XYZ()
+	fade := 1 - gxsl.Smoothstep(0, 0.1, gxsl.Fwidth(varyings.FragTexCoord.X))
+	gxsl.FragColor = gxsl.Vec4FromVec3(gxsl.ClampScalar(color, 0, 1), fade)
+}
+
+// Particles are updated by a compute shader, with the same struct on the CPU
+type Particle struct {
+	Position gxsl.Vec2
+	Velocity gxsl.Vec2
+	Life     float64
+	Fade     float64
+}
+
+type ParticleParams struct {
+	DeltaTime float64
+	Gravity   gxsl.Vec2
+}
+
+//gxsl:compute
+//gxsl:profile 430
+//gxsl:workgroup 64
+func particleShader(uniforms ParticleParams, particles []Particle) {
+	i := int(gxsl.GlobalInvocationID.X)
+	if i >= len(particles) {
+		return
+	}
+	particle := particles[i]
+	particle.Velocity = particle.Velocity.Add(uniforms.Gravity.Scale(uniforms.DeltaTime))
+	particle.Position = particle.Position.Add(particle.Velocity.Scale(uniforms.DeltaTime))
+	particle.Life -= particle.Fade * uniforms.DeltaTime
+	particles[i] = particle
 }
 
 //
 // Main
 //
 
+func check(val bool) {
+	if val {
+		println("ok")
+	} else {
+		println("not ok")
+	}
+}
+
+func near(a, b gxsl.Vec4) bool {
+	d := a.Add(b.Negate())
+	return gxsl.Dot(d, d) < 0.0001
+}
+
+func testShadersOnCPU() {
+	// Vertex
+	{
+		uniforms := SpriteParams{
+			Model: gxsl.NewMat4(
+				gxsl.Vec4{2, 0, 0, 0},
+				gxsl.Vec4{0, 2, 0, 0},
+				gxsl.Vec4{0, 0, 1, 0},
+				gxsl.Vec4{0, 0, 0, 1},
+			),
+			Offset: gxsl.Vec4{1, 2, 0, 0},
+		}
+		attributes := SpriteAttributes{
+			VertexPosition: gxsl.Vec2{3, 4},
+			VertexTexCoord: gxsl.Vec2{0.25, 0.5},
+			VertexColor:    gxsl.Vec4{1, 1, 1, 1},
+		}
+		varyings := Varyings{}
+		spriteShader(uniforms, attributes, &varyings)
+		check(gxsl.Position == gxsl.Vec4{7, 10, 0, 1})
+		check(varyings.FragTexCoord == gxsl.Vec2{0.25, 0.5})
+	}
+
+	// Fragment, sampling the left half of a two-pixel texture
+	{
+		uniforms := RedTextureParams{
+			ColDiffuse: gxsl.Vec4{1, 1, 1, 1},
+			Texture0: gxsl.Sampler2D{
+				Width:  2,
+				Height: 1,
+				Pixels: []gxsl.Vec4{{1, 1, 1, 1}, {0, 0, 0, 0}},
+			},
+		}
+		varyings := Varyings{FragTexCoord: gxsl.Vec2{0.25, 0.5}, FragColor: gxsl.Vec4{1, 1, 1, 1}}
+		redTextureShader(uniforms, varyings)
+		check(near(gxsl.FragColor, gxsl.Vec4{50, 10, 10, 50}))
+		varyings.FragTexCoord = gxsl.Vec2{0.75, 0.5}
+		redTextureShader(uniforms, varyings)
+		check(near(gxsl.FragColor, gxsl.Vec4{0, 0, 0, 0}))
+	}
+
+	// Swizzles and built-in functions
+	{
+		varyings := Varyings{FragTexCoord: gxsl.Vec2{0, 0}, FragColor: gxsl.Vec4{1, 0, 0, 0.5}}
+		toneShader(varyings)
+		check(near(gxsl.FragColor, gxsl.Vec4{1, 0, 0, 0.5}))
+		varyings.FragTexCoord.X = 1
+		toneShader(varyings)
+		check(near(gxsl.FragColor, gxsl.Vec4{0.25, 0.25, 0.25, 0.5}))
+		check(gxsl.Vec4{1, 2, 3, 4}.ZW() == gxsl.Vec2{3, 4})
+		check(gxsl.Vec2{1, 2}.YX() == gxsl.Vec2{2, 1})
+		check(gxsl.Length(gxsl.Vec2{3, 4}) == 5)
+		check(gxsl.Cross(gxsl.Vec3{1, 0, 0}, gxsl.Vec3{0, 1, 0}) == gxsl.Vec3{0, 0, 1})
+		check(gxsl.Reflect(gxsl.Vec2{1, -1}, gxsl.Vec2{0, 1}) == gxsl.Vec2{1, 1})
+	}
+
+	// Cube maps, one texel per face
+	{
+		uniforms := SkyParams{
+			Sky: gxsl.SamplerCube{
+				Size:   1,
+				Pixels: []gxsl.Vec4{{1, 0, 0, 1}, {0, 1, 0, 1}, {0, 0, 1, 1}, {1, 1, 0, 1}, {2, 0.5, 0, 1}, {0, 1, 1, 1}},
+			},
+			Rotation: gxsl.NewMat3(gxsl.Vec3{1, 0, 0}, gxsl.Vec3{0, 1, 0}, gxsl.Vec3{0, 0, 1}),
+		}
+		varyings := Varyings{FragTexCoord: gxsl.Vec2{1.5, 0.5}}
+		skyShader(uniforms, varyings)
+		check(gxsl.FragColor == gxsl.Vec4{1, 0.5, 0, 1})
+		uniforms.Rotation = gxsl.NewMat3(gxsl.Vec3{0, 0, 1}, gxsl.Vec3{0, 1, 0}, gxsl.Vec3{-1, 0, 0})
+		skyShader(uniforms, varyings)
+		check(near(gxsl.FragColor, gxsl.Vec4{1, 0, 0, 1}))
+		check(gxsl.TextureCube(uniforms.Sky, gxsl.Vec3{0, -2, 1}) == gxsl.Vec4{1, 1, 0, 1})
+	}
+
+	// Matrix functions
+	{
+		m := gxsl.NewMat4(gxsl.Vec4{2, 0, 0, 0}, gxsl.Vec4{1, 3, 0, 0}, gxsl.Vec4{0, 1, 4, 0}, gxsl.Vec4{5, 6, 7, 1})
+		v := gxsl.Vec4{1, 2, 3, 1}
+		check(gxsl.Determinant(m) == 24)
+		check(gxsl.Distance(gxsl.Inverse(m).Transform(m.Transform(v)), v) < 0.001)
+		check(gxsl.Transpose(m).Transform(gxsl.Vec4{1, 0, 0, 0}) == gxsl.Vec4{2, 1, 0, 5})
+		check(gxsl.MatrixCompMult(m, m).Transform(gxsl.Vec4{0, 1, 0, 0}) == gxsl.Vec4{1, 9, 0, 0})
+		m3 := gxsl.NewMat3(gxsl.Vec3{1, 2, 0}, gxsl.Vec3{0, 1, 3}, gxsl.Vec3{4, 0, 1})
+		v3 := gxsl.Vec3{3, -1, 2}
+		check(gxsl.Determinant(m3) == 25)
+		check(gxsl.Distance(gxsl.Inverse(m3).Transform(m3.Transform(v3)), v3) < 0.001)
+		m2 := gxsl.NewMat2(gxsl.Vec2{4, 2}, gxsl.Vec2{7, 6})
+		check(gxsl.Determinant(m2) == 10)
+		check(gxsl.Distance(gxsl.Inverse(m2).Transform(gxsl.Vec2{1, 0}), gxsl.Vec2{0.6, -0.2}) < 0.001)
+	}
+
+	// Common functions and their scalar overloads
+	{
+		check(gxsl.Mod(-1.0, 3.0) == 2)
+		check(gxsl.ModScalar(gxsl.Vec2{-1, 4}, 3) == gxsl.Vec2{2, 1})
+		check(gxsl.MinScalar(gxsl.Vec3{1, 5, 3}, 2) == gxsl.Vec3{1, 2, 2})
+		check(gxsl.MaxScalar(gxsl.Vec3{1, 5, 3}, 2) == gxsl.Vec3{2, 5, 3})
+		check(gxsl.ClampScalar(gxsl.Vec2{-1, 2}, 0, 1) == gxsl.Vec2{0, 1})
+		check(gxsl.StepScalar(0.5, gxsl.Vec2{0.25, 0.75}) == gxsl.Vec2{0, 1})
+		check(gxsl.SmoothstepScalar(0, 2, gxsl.Vec2{1, 3}) == gxsl.Vec2{0.5, 1})
+		check(gxsl.MixEach(gxsl.Vec2{0, 0}, gxsl.Vec2{4, 4}, gxsl.Vec2{0.25, 1}) == gxsl.Vec2{1, 4})
+		check(gxsl.RoundEven(2.5) == 2 && gxsl.Trunc(-1.5) == -1)
+		check(gxsl.DFdx(gxsl.Vec2{1, 2}) == gxsl.Vec2{0, 0})
+	}
+
+	// Swizzles of every combination of components
+	{
+		check(gxsl.Vec4{1, 2, 3, 4}.WZYX() == gxsl.Vec4{4, 3, 2, 1})
+		check(gxsl.Vec3{1, 2, 3}.ZYX() == gxsl.Vec3{3, 2, 1})
+		check(gxsl.Vec3{1, 2, 3}.XZY() == gxsl.Vec3{1, 3, 2})
+		check(gxsl.Vec2{1, 2}.XXYY() == gxsl.Vec4{1, 1, 2, 2})
+	}
+
+	// Multiple render targets
+	{
+		outputs := SplitOutputs{}
+		splitShader(Varyings{FragTexCoord: gxsl.Vec2{0.5, 1}, FragColor: gxsl.Vec4{0, 1, 0, 1}}, &outputs)
+		check(outputs.Color == gxsl.Vec4{0, 1, 0, 1})
+		check(outputs.TexCoord == gxsl.Vec4{0.5, 1, 3, 0})
+	}
+
+	// Compute
+	{
+		uniforms := ParticleParams{DeltaTime: 0.5, Gravity: gxsl.Vec2{0, -2}}
+		particles := []Particle{
+			{Velocity: gxsl.Vec2{1, 0}, Life: 1, Fade: 1},
+			{Position: gxsl.Vec2{1, 1}, Life: 1, Fade: 0.5},
+		}
+		for i := 0; i < 4; i++ { // More invocations than particles, like a whole workgroup
+			gxsl.GlobalInvocationID = gxsl.UVec3{uint(i), 0, 0}
+			particleShader(uniforms, particles)
+		}
+		check(particles[0].Position == gxsl.Vec2{0.5, -0.5} && particles[0].Life == 0.5)
+		check(particles[1].Position == gxsl.Vec2{1, 0.5} && particles[1].Life == 0.75)
+	}
+}
+
 func main() {
+	testShadersOnCPU()
 }
@@ -0,0 +1,149 @@
+#pragma once
+
+#include <cstdio>
+#include <cstring>
+
+namespace gx {
+struct String;
+enum class ListEdit;
+template<typename T, typename Visitor>
+bool inspect(T *val, Visitor &visitor);
+}
+
+// Draws an inspector panel as indented lines of text, standing in for a UI
+// library in tests. Applies one scripted edit to the value at `editPath`, like
+// `stats.speed` or `tags[1]`, as if the user had made it.
+template<typename ListEdit = gx::ListEdit, typename String = gx::String>
+struct TextInspector {
+  const char *editPath = "";
+  const char *editAction = ""; // "set", "select", "add", "remove", "up" or "down"
+  double editNumber = 0;
+  const char *editText = "";
+
+  char text[2048] {};
+  int textLen = 0;
+  char path[256] {};
+  int pathLens[32] {};
+  int depth = 0;
+
+  void line(const char *label, const char *value) {
+    for (auto i = 1; i < depth; ++i) {
+      textLen += std::snprintf(text + textLen, sizeof(text) - textLen, "  ");
+    }
+    textLen += std::snprintf(text + textLen, sizeof(text) - textLen, "%s: %s\n", label, value);
+  }
+
+  void push(const char *label) {
+    pathLens[depth++] = std::strlen(path);
+    auto sep = path[0] && label[0] != '[' ? "." : "";
+    std::snprintf(path + std::strlen(path), sizeof(path) - std::strlen(path), "%s%s", sep, label);
+  }
+
+  void pop() {
+    path[pathLens[--depth]] = '\0';
+  }
+
+  bool editing(const char *label, const char *action) {
+    push(label);
+    auto result = std::strcmp(path, editPath) == 0 && std::strcmp(editAction, action) == 0;
+    pop();
+    return result;
+  }
+
+  bool beginStruct(const char *label) {
+    if (label[0]) {
+      line(label, "");
+    }
+    push(label);
+    return true;
+  }
+
+  void endStruct() {
+    pop();
+  }
+
+  bool beginList(const char *label, int size, bool resizable) {
+    char value[32];
+    std::snprintf(value, sizeof(value), "%d items%s", size, resizable ? "" : " (fixed)");
+    line(label, value);
+    push(label);
+    return true;
+  }
+
+  ListEdit editItem(int index, int size) {
+    char label[16];
+    std::snprintf(label, sizeof(label), "[%d]", index);
+    if (editing(label, "remove")) {
+      return ListEdit::Remove;
+    } else if (editing(label, "up")) {
+      return ListEdit::MoveUp;
+    } else if (editing(label, "down")) {
+      return ListEdit::MoveDown;
+    }
+    return ListEdit::None;
+  }
+
+  ListEdit endList() {
+    auto add = std::strcmp(path, editPath) == 0 && std::strcmp(editAction, "add") == 0;
+    pop();
+    return add ? ListEdit::Add : ListEdit::None;
+  }
+
+  bool boolean(const char *label, bool &val) {
+    auto changed = editing(label, "set");
+    if (changed) {
+      val = editNumber != 0;
+    }
+    line(label, val ? "true" : "false");
+    return changed;
+  }
+
+  template<typename T>
+  bool number(const char *label, T &val, double min, double max) {
+    auto changed = editing(label, "set");
+    if (changed) {
+      val = T(editNumber < min ? min : editNumber > max ? max : editNumber);
+    }
+    char value[32];
+    std::snprintf(value, sizeof(value), "%g", double(val));
+    line(label, value);
+    return changed;
+  }
+
+  bool string(const char *label, String &val) {
+    auto changed = editing(label, "set");
+    if (changed) {
+      val = String(editText);
+    }
+    char value[256];
+    std::snprintf(value, sizeof(value), "\"%s\"", (const char *)val);
+    line(label, value);
+    return changed;
+  }
+
+  void enumeration(const char *label, const char *current, auto &&forEachOption) {
+    auto selecting = editing(label, "select");
+    char value[256];
+    auto valueLen = std::snprintf(value, sizeof(value), "%s of", current);
+    forEachOption([&](const char *name, bool selected) {
+      valueLen += std::snprintf(value + valueLen, sizeof(value) - valueLen, selected ? " [%s]" : " %s", name);
+      return selecting && std::strcmp(name, editText) == 0;
+    });
+    line(label, value);
+  }
+
+  String result() {
+    return String(text);
+  }
+};
+
+auto inspectToText(auto *val) {
+  TextInspector<> inspector;
+  gx::inspect(val, inspector);
+  return inspector.result();
+}
+
+bool inspectEdit(auto *val, const char *path, const char *action, double number, const char *text) {
+  TextInspector<> inspector { .editPath = path, .editAction = action, .editNumber = number, .editText = text };
+  return gx::inspect(val, inspector);
+}
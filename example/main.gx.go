@@ -1,6 +1,13 @@
 //gx:include <string.h>
 //gx:include "rect.hh"
 //gx:include "sum_fields.hh"
+//gx:include "type_attribs.hh"
+//gx:include "methods.hh"
+//gx:include "registry.hh"
+//gx:include "migrate.hh"
+//gx:include "inspect.hh"
+//gx:register RegisterComponent when embeds github.com/nikki93/gx/example.Component
+//gx:register RegisterSystem when embeds github.com/nikki93/gx/example.System
 
 package main
 
@@ -185,14 +192,40 @@ func testStruct() {
 		check(s.inner.z == 4)
 	}
 	{
-		// Out-of-order elements in struct literal no longer allowed
-		//s := Outer{
-		//  inner: Inner{
-		//    z: 4,
-		//  },
-		//  y: 3,
-		//  x: 2,
-		//}
+		s := Outer{
+			inner: Inner{
+				z: 4,
+			},
+			y: 3,
+			x: 2,
+		}
+		check(s.x == 2)
+		check(s.y == 3)
+		check(s.inner.z == 4)
+	}
+	{
+		s := Outer{y: 3, inner: Inner{z: 4}}
+		check(s.x == 0)
+		check(s.y == 3)
+		check(s.inner.z == 4)
+	}
+	{
+		order := 0
+		next := func() int {
+			order = 10*order + 1
+			return order
+		}
+		s := Outer{inner: Inner{z: next()}, y: next(), x: next()}
+		check(s.inner.z == 1)
+		check(s.y == 11)
+		check(s.x == 111)
+	}
+	{
+		h := HasDefaults{bar: 1.5, foo: 2}
+		check(h.foo == 2)
+		check(h.bar == 1.5)
+		check(h.point.x == 1)
+		check(h.point.y == 2)
 	}
 	{
 		i := 42
@@ -305,6 +338,114 @@ func testGenerics() {
 	}
 }
 
+//
+// Equality
+//
+
+type Segment struct {
+	a, b  Point
+	label string
+}
+
+func testEquality() {
+	{
+		p := Point{1, 2}
+		q := Point{1, 2}
+		r := Point{2, 1}
+		check(p == q)
+		check(p != r)
+		check(p == Point{1, 2})
+	}
+	{
+		s := Segment{Point{1, 2}, Point{3, 4}, "s"}
+		t := s
+		check(s == t)
+		t.label = "t"
+		check(s != t)
+		t.label = "s"
+		t.b.y = 5
+		check(s != t)
+	}
+	{
+		a := [3]int{1, 2, 3}
+		b := [3]int{1, 2, 3}
+		check(a == b)
+		b[2] = 4
+		check(a != b)
+		c := [2]Point{{1, 2}, {3, 4}}
+		d := [2]Point{{1, 2}, {3, 4}}
+		check(c == d)
+		d[1].x = 0
+		check(c != d)
+	}
+	{
+		p := Point{1, 2}
+		q := Point{1, 2}
+		pa := &p
+		pb := &p
+		check(pa == pb)
+		check(pa != &q)
+		check(pa != nil)
+	}
+	{
+		h := Holder[int]{42}
+		check(h == Holder[int]{42})
+		check(h != Holder[int]{14})
+	}
+}
+
+//
+// Anonymous structs
+//
+
+type Tuning struct {
+	Name  string
+	Range struct {
+		Min, Max int
+	}
+}
+
+var scores = []struct {
+	name  string
+	score int
+}{
+	{"a", 1},
+	{"b", 2},
+}
+
+func testAnonymousStructs() {
+	{
+		t := Tuning{Name: "speed"}
+		t.Range.Min = 1
+		t.Range.Max = 10
+		check(t.Range.Max-t.Range.Min == 9)
+	}
+	{
+		sum := 0
+		for _, entry := range scores {
+			sum += entry.score
+		}
+		check(sum == 3)
+		check(scores[1].name == "b")
+	}
+	{
+		p := struct{ x, y int }{1, 2}
+		q := struct{ x, y int }{y: 2, x: 1}
+		check(p == q)
+	}
+	{
+		cases := []struct {
+			in, out int
+		}{
+			{1, 1},
+			{6, 8},
+		}
+		for _, c := range cases {
+			check(fib(c.in) == c.out)
+		}
+	}
+}
+
 //
 // Lambdas
 //
@@ -607,6 +748,25 @@ func apply(val int, fn func(int) int) int {
 
 var globalApplied = apply(3, func(i int) int { return 2 * i })
 
+var globalViaFunc = laterGlobalPlusOne() // Dependency through function body
+
+func laterGlobalPlusOne() int {
+	return laterGlobal + 1
+}
+
+var laterGlobal = 5
+
+var fooInitialized = foo.Initialized
+var initOrder []int
+
+func init() {
+	initOrder = append(initOrder, globalX)
+}
+
+func init() {
+	initOrder = append(initOrder, 2)
+}
+
 type Enum int
 
 const (
@@ -636,6 +796,14 @@ func testGlobalVariables() {
 	{
 		check(globalApplied == 6)
 	}
+	{
+		check(globalViaFunc == 6)
+		check(fooInitialized)
+		check(len(initOrder) == 3)
+		check(initOrder[0] == 1)
+		check(initOrder[1] == 23)
+		check(initOrder[2] == 2)
+	}
 	{
 		check(ZeroEnum == 0)
 		check(OneEnum == 1)
@@ -643,6 +811,75 @@ func testGlobalVariables() {
 	}
 }
 
+//
+// Constants
+//
+
+const KB = 1 << 10
+const Big = 1 << 40 // Untyped, doesn't fit in `int`
+const BigShifted = Big >> 30
+
+type Size int
+
+const (
+	SizeB Size = 1 << (10 * iota)
+	SizeKB
+	SizeMB
+)
+
+const (
+	ColorRed = iota
+	ColorGreen
+	ColorBlue
+)
+
+const Third = 1.0 / 3
+const Greeting = "hello, " + "world\n"
+
+func testConstants() {
+	{
+		kb := KB
+		check(kb == 1024)
+		shifted := BigShifted
+		check(shifted == 1024)
+	}
+	{
+		kb := SizeKB
+		check(kb == 1024)
+		mb := SizeMB
+		check(mb == 1048576)
+		blue := ColorBlue
+		check(blue == 2)
+	}
+	{
+		third := Third
+		check(third > 0.33 && third < 0.34)
+		one := Third * 3 // Exact arithmetic on untyped constants
+		check(one == 1)
+	}
+	{
+		greeting := Greeting
+		check(len(greeting) == 13)
+		check(greeting[12] == '\n')
+		check(greeting == "hello, world\n")
+	}
+	{
+		mask := uint(1 << 31)
+		check(mask == 2147483648)
+		check(mask>>31 == 1)
+	}
+	{
+		var small int8 = -128
+		medium := int16(-32768)
+		var rune32 int32 = 'x'
+		big := int64(-9223372036854775808)
+		check(small == -128 && medium == -32768 && rune32 == 120)
+		check(big < 0 && big+1 == -9223372036854775807)
+		check(int64(1)<<40 == 1099511627776)
+		check(int64(small)+int64(medium) == -32896)
+	}
+}
+
 //
 // Imports
 //
@@ -733,17 +970,427 @@ func testConversions() {
 // Meta
 //
 
+// Declares the fields of `GX_FIELD_ATTRIBS` so `attribs` tags are checked
+//
+//gx:fieldattribs
+//gx:extern SumFieldsAttribs
+type SumFieldsAttribs struct {
+	Name  string
+	Twice bool
+	Scale int
+	Label string
+	Min   float64
+	Max   float64
+}
+
 type Nums struct {
 	A, B, C int
 	D       int `attribs:"twice"`
+	E       int `attribs:"label='Tripled E', scale=3"`
 }
 
 //gx:extern sumFields
 func sumFields(val interface{}) int
 
+//gx:extern firstFieldLabel
+func firstFieldLabel(val interface{}) string
+
+//gx:extern resetFields
+func resetFields(val interface{})
+
+// Declares the fields of `GX_TYPE_ATTRIBS` so `//gx:attribs` directives are
+// checked
+//
+//gx:typeattribs
+//gx:extern InspectorTypeAttribs
+type InspectorTypeAttribs struct {
+	EditorHidden bool //gx:extern editor_hidden
+	Category     string
+}
+
+//gx:attribs editor_hidden, category=Physics
+type RigidBody struct {
+	Mass float32
+}
+
+//gx:extern typeName
+func typeName(val interface{}) string
+
+//gx:extern typePackagePath
+func typePackagePath(val interface{}) string
+
+//gx:extern typeID
+func typeID(val interface{}) uint64
+
+//gx:extern typeEditorHidden
+func typeEditorHidden(val interface{}) bool
+
+//gx:extern typeCategory
+func typeCategory(val interface{}) string
+
+// Declares the fields of `GX_METHOD_ATTRIBS` so `//gx:reflect` directives are
+// checked
+//
+//gx:methodattribs
+//gx:extern InspectorMethodAttribs
+type InspectorMethodAttribs struct {
+	Name  string
+	Label string
+}
+
+type Door struct {
+	Openness float32
+	Locked   bool
+}
+
+//gx:reflect label='Open door'
+func (d *Door) Open() {
+	if !d.Locked {
+		d.Openness = 1
+	}
+}
+
+//gx:reflect
+func (d *Door) OpenBy(amount float32) {
+	d.Openness += amount
+}
+
+//gx:reflect
+func (d Door) IsOpen() bool {
+	return d.Openness > 0
+}
+
+func (d *Door) Lock() {
+	d.Locked = true
+}
+
+//gx:extern callMethod
+func callMethod(val interface{}, name string) bool
+
+//gx:extern callMethodWithNumber
+func callMethodWithNumber(val interface{}, name string, arg float32) bool
+
+//gx:extern methodLabels
+func methodLabels(val interface{}) string
+
 func testMeta() {
-	n := Nums{1, 2, 3, 4}
-	check(sumFields(n) == 14)
+	n := Nums{1, 2, 3, 4, 5}
+	check(sumFields(n) == 29)
+	check(strcmp(firstFieldLabel(n), "Tripled E") == 0)
+
+	body := RigidBody{}
+	check(strcmp(typeName(body), "RigidBody") == 0)
+	check(strcmp(typePackagePath(body), "github.com/nikki93/gx/example") == 0)
+	check(typeEditorHidden(body))
+	check(strcmp(typeCategory(body), "Physics") == 0)
+	check(!typeEditorHidden(n))
+	check(strcmp(typeCategory(n), "") == 0)
+	check(typeID(body) != typeID(n))
+	check(strcmp(typeName(Holder[int]{}), "Holder[int]") == 0)
+	check(typeID(Holder[int]{}) != typeID(Holder[Point]{}))
+	check(strcmp(typeName(foo.Bar{}), "Bar") == 0)
+	check(strcmp(typePackagePath(foo.Bar{}), "github.com/nikki93/gx/example/foo") == 0)
+
+	door := Door{}
+	check(strcmp(methodLabels(&door), "Open door;openBy;isOpen;") == 0)
+	check(callMethod(&door, "open"))
+	check(door.Openness == 1)
+	check(callMethodWithNumber(&door, "openBy", 0.5))
+	check(door.Openness == 1.5)
+	check(!callMethod(&door, "openBy"))
+	check(!callMethodWithNumber(&door, "open", 0.5))
+	check(!callMethod(&door, "lock"))
+	check(!door.Locked)
+}
+
+//
+// Registration
+//
+
+type Component struct{}
+
+type System struct{}
+
+type Health struct {
+	Component
+	Value int
+}
+
+type Gravity struct {
+	System
+	Component // Registered in both categories
+	Strength  float32
+}
+
+//gx:extern TypeRegistry
+type TypeRegistry struct{}
+
+//gx:extern componentTypes
+var componentTypes TypeRegistry
+
+//gx:extern systemTypes
+var systemTypes TypeRegistry
+
+//gx:extern isRegistered
+func isRegistered(registry *TypeRegistry, name string) bool
+
+func testRegistration() {
+	check(isRegistered(&componentTypes, "Health"))
+	check(isRegistered(&componentTypes, "Gravity"))
+	check(isRegistered(&systemTypes, "Gravity"))
+	check(!isRegistered(&systemTypes, "Health"))
+	check(!isRegistered(&componentTypes, "Point"))
+}
+
+//
+// JSON
+//
+
+//gx:extern gx::toJSON
+func toJSON(val interface{}) string
+
+//gx:extern gx::fromJSON
+func fromJSON(val interface{}, json string) string
+
+type JSONStats struct {
+	Speed float32 `default:"2.5"`
+	Level uint8
+	Score uint64
+	Alive bool
+}
+
+type JSONEnemy struct {
+	Name  string `attribs:"uppercase"`
+	Stats JSONStats
+	Tags  []string
+	Cell  [2]int
+}
+
+type JSONScene struct {
+	Enemies []JSONEnemy
+}
+
+func testJSON() {
+	{
+		scene := JSONScene{Enemies: []JSONEnemy{
+			{Name: "bat \"B\"", Stats: JSONStats{Speed: 1.5, Level: 3, Score: 18446744073709551615, Alive: true}, Tags: []string{"flying"}, Cell: [2]int{1, 2}},
+			{Name: "slime"},
+		}}
+		json := toJSON(scene)
+		check(json == `{"enemies":[{"Name":"bat \"B\"","stats":{"speed":1.5,"level":3,"score":18446744073709551615,"alive":true},"tags":["flying"],"cell":[1,2]},{"Name":"slime","stats":{"speed":2.5,"level":0,"score":0,"alive":false},"tags":[],"cell":[0,0]}]}`)
+		read := JSONScene{}
+		check(fromJSON(&read, json) == "")
+		check(len(read.Enemies) == 2)
+		check(read.Enemies[0].Name == "bat \"B\"")
+		check(read.Enemies[0].Stats.Score == 18446744073709551615)
+		check(read.Enemies[0].Tags[0] == "flying")
+		check(read.Enemies[0].Cell[1] == 2)
+		check(toJSON(read) == json)
+	}
+	{
+		enemy := JSONEnemy{Name: "old", Tags: []string{"old"}}
+		check(fromJSON(&enemy, ` { "Name": "ghost\u00e9", "stats": { "level": 7 }, "unknown": [1, {"a": null}] } `) == "")
+		check(enemy.Name == "ghost\u00e9")
+		check(enemy.Stats.Level == 7)
+		check(enemy.Stats.Speed == 2.5) // Missing fields get defaults
+		check(len(enemy.Tags) == 0)
+	}
+	{
+		scene := JSONScene{}
+		check(fromJSON(&scene, `{"enemies":[{}, {"stats":{"speed":"fast"}}]}`) == "$.enemies[1].stats.speed: expected a number")
+		check(fromJSON(&scene, `{"enemies":[{"stats":{"level":300}}]}`) == "$.enemies[0].stats.level: integer out of range")
+		check(fromJSON(&scene, `{"enemies":[{"cell":[1,2,3]}]}`) == "$.enemies[0].cell[2]: too many elements")
+		check(fromJSON(&scene, `{"enemies":[]} x`) == "$: unexpected content after value")
+	}
+	{
+		zero := float32(0)
+		enemy := JSONEnemy{Stats: JSONStats{Speed: zero / zero}}
+		json := toJSON(enemy)
+		check(json == `{"Name":"","stats":{"speed":null,"level":0,"score":0,"alive":false},"tags":[],"cell":[0,0]}`)
+		read := JSONEnemy{}
+		check(fromJSON(&read, json) == "")
+		check(read.Stats.Speed != read.Stats.Speed) // Non-finite numbers are written as null and read back as NaN
+	}
+	{
+		enemy := JSONEnemy{}
+		check(fromJSON(&enemy, `{"Name":"\ud83d\ude00"}`) == "")
+		check(enemy.Name == "\U0001F600")
+		check(fromJSON(&enemy, `{"Name":"\ud83d"}`) == "$.Name: invalid unicode escape")
+		check(fromJSON(&enemy, `{"Name":"\ud83d\u0041"}`) == "$.Name: invalid unicode escape")
+		check(fromJSON(&enemy, `{"Name":"\ude00"}`) == "$.Name: invalid unicode escape")
+	}
+}
+
+//
+// Binary
+//
+
+//gx:extern gx::toBinary
+func toBinary(val interface{}) []uint8
+
+//gx:extern gx::fromBinary
+func fromBinary(val interface{}, data []uint8, allowSchemaChanges bool) string
+
+type SaveV1 struct {
+	Health int `attribs:"id=1"`
+	Name   string
+}
+
+// Reordered, with `Health` renamed but keeping its id
+type SaveV2 struct {
+	Mana      int `default:"5"`
+	Name      string
+	HitPoints int `attribs:"id=1"`
+}
+
+type SaveBadHealth struct {
+	Health float32 `attribs:"id=1"`
+}
+
+func testBinary() {
+	{
+		scene := JSONScene{Enemies: []JSONEnemy{
+			{Name: "bat", Stats: JSONStats{Speed: -1.5, Level: 200, Score: 18446744073709551615, Alive: true}, Tags: []string{"flying", ""}, Cell: [2]int{-1, 300}},
+			{Name: "slime"},
+		}}
+		data := toBinary(scene)
+		read := JSONScene{}
+		check(fromBinary(&read, data, false) == "")
+		check(toJSON(read) == toJSON(scene))
+		check(len(data) < len(toJSON(scene))/2)
+	}
+	{
+		save := SaveV1{Health: -7, Name: "hero"}
+		data := toBinary(save)
+		check(fromBinary(&save, data, false) == "")
+		check(save.Health == -7)
+
+		// Changed schemas are rejected unless explicitly allowed, then fields
+		// are matched by id and new ones get their defaults
+		newer := SaveV2{}
+		check(fromBinary(&newer, data, false) != "")
+		check(fromBinary(&newer, data, true) == "")
+		check(newer.HitPoints == -7)
+		check(newer.Name == "hero")
+		check(newer.Mana == 5)
+		older := SaveV1{}
+		check(fromBinary(&older, toBinary(SaveV2{HitPoints: 3, Name: "mage", Mana: 9}), true) == "")
+		check(older.Health == 3 && older.Name == "mage")
+		bad := SaveBadHealth{}
+		check(fromBinary(&bad, data, true) == "$.health: field has a different type in the data")
+
+		truncated := []uint8{}
+		for i := 0; i < len(data)-1; i++ {
+			truncated = append(truncated, data[i])
+		}
+		check(fromBinary(&save, truncated, false) == "$: length out of range")
+	}
+}
+
+//
+// Enums
+//
+
+//gx:extern nameOf
+func nameOf(val interface{}) string
+
+//gx:extern gx::parseEnum
+func parseEnum(val interface{}, name string) bool
+
+//gx:extern forEachEnumValue
+func forEachSizeValue(val Size, f func(name string, value Size))
+
+type EnumHolder struct {
+	Amount Size
+	State  foo.Mode
+	Level  foo.Priority
+}
+
+func testEnums() {
+	{
+		check(nameOf(SizeKB) == "SizeKB")
+		check(nameOf(Size(3)) == "Size(3)")
+		check(nameOf(foo.ModeRun) == "ModeRun")
+		names := []string{}
+		total := 0
+		forEachSizeValue(SizeB, func(name string, value Size) {
+			names = append(names, name)
+			total += int(value)
+		})
+		check(len(names) == 3 && names[0] == "SizeB" && names[2] == "SizeMB")
+		check(total == 1049601)
+	}
+	{
+		s := SizeB
+		check(parseEnum(&s, "SizeMB"))
+		check(s == SizeMB)
+		check(!parseEnum(&s, "SizeGB"))
+		check(s == SizeMB)
+	}
+	{
+		s := SizeKB
+		s *= 2
+		s++
+		next := s + SizeB // Arithmetic keeps the enum type
+		check(nameOf(next) == "Size(2050)")
+		check(nameOf(next-2050+SizeKB) == "SizeKB")
+	}
+	{
+		holder := EnumHolder{Amount: SizeKB, State: foo.ModeRun, Level: foo.PriorityHigh}
+		json := toJSON(holder)
+		check(json == `{"amount":"SizeKB","state":"ModeRun","level":1}`)
+		read := EnumHolder{}
+		check(fromJSON(&read, json) == "")
+		check(read.Amount == SizeKB && read.State == foo.ModeRun && read.Level == foo.PriorityHigh)
+		check(fromJSON(&read, `{"amount":5}`) == "")
+		check(read.Amount == 5)
+		check(toJSON(read) == `{"amount":5,"state":"ModeIdle","level":0}`)
+		check(fromJSON(&read, `{"state":"ModeWalk"}`) == "$.state: unknown enum value")
+		check(fromBinary(&read, toBinary(holder), false) == "")
+		check(read.State == foo.ModeRun)
+	}
+}
+
+//
+// Layout
+//
+
+//gx:layout
+type PlayerStateV1 struct {
+	Name   string
+	Health int
+	Speed  float32
+	Items  []string
+	pos    Point
+}
+
+//gx:layout
+type PlayerStateV2 struct {
+	Health int
+	Name   string
+	Speed  int `default:"3"` // Type changed, so reset to its default
+	Mana   int `default:"50"`
+	Items  []string
+	pos    Point
+}
+
+//gx:extern migrateFromOld
+func migrateFromOld(old interface{}, newValue interface{})
+
+//gx:extern layoutDefault
+func layoutDefault(val interface{}, name string) string
+
+func testLayout() {
+	state := PlayerStateV2{}
+	migrateFromOld(PlayerStateV1{Name: "hero", Health: 7, Speed: 1.5, Items: []string{"sword"}, pos: Point{1, 2}}, &state)
+	check(state.Name == "hero")
+	check(state.Health == 7)
+	check(state.Speed == 3)
+	check(state.Mana == 50)
+	check(len(state.Items) == 1 && state.Items[0] == "sword")
+	check(state.pos.x == 1 && state.pos.y == 2)
+	check(strcmp(layoutDefault(&state, "Mana"), "50") == 0)
+	check(strcmp(layoutDefault(&state, "Name"), "") == 0)
 }
 
 //
@@ -753,15 +1400,120 @@ func testMeta() {
 type HasDefaults struct {
 	foo   int     `default:"42"`
 	bar   float32 `default:"6.4"`
-	point Point   `default:"{ 1, 2 }"`
+	point Point   `default:"Point{1, 2}"`
 }
 
 func testDefaults() {
-	h := HasDefaults{}
-	check(h.foo == 42)
-	check(h.bar == 6.4)
-	check(h.point.x == 1)
-	check(h.point.y == 2)
+	{
+		h := HasDefaults{}
+		check(h.foo == 42)
+		check(h.bar == 6.4)
+		check(h.point.x == 1)
+		check(h.point.y == 2)
+	}
+	{
+		var h HasDefaults
+		var n, m int = 3, 4
+		var f float32
+		var name, scale = "pip", 1.5
+		check(h.foo == 42)
+		check(h.point.y == 2)
+		check(n == 3 && m == 4)
+		check(name == "pip" && scale == 1.5)
+		check(f == 0)
+	}
+	{
+		e := JSONEnemy{}
+		check(e.Stats.Speed == 2.5)
+		check(e.Stats.Level == 0)
+	}
+	{
+		s := JSONStats{Speed: 9, Level: 3, Alive: true}
+		resetFields(&s)
+		check(s.Speed == 2.5)
+		check(s.Level == 0)
+		check(!s.Alive)
+	}
+	{
+		states := []PlayerStateV2{{Name: "a"}}
+		states = append(states, PlayerStateV2{})
+		check(states[0].Mana == 50)
+		check(states[1].Mana == 50)
+		check(states[1].Health == 0)
+	}
+}
+
+//
+// Inspect
+//
+
+type InspectedStats struct {
+	Speed float32 `attribs:"min=0, max=10"`
+	Level int
+}
+
+type Inspected struct {
+	Name  string
+	State foo.Mode
+	Alive bool
+	Stats InspectedStats
+	Tags  []string
+	Grid  [2]int
+	Mana  []int `attribs:"min=0, max=100"`
+}
+
+//gx:extern inspectToText
+func inspectToText(val interface{}) string
+
+//gx:extern inspectEdit
+func inspectEdit(val interface{}, path string, action string, number float64, text string) bool
+
+func testInspect() {
+	val := Inspected{
+		Name:  "crate",
+		State: foo.ModeIdle,
+		Stats: InspectedStats{Speed: 2.5, Level: 3},
+		Tags:  []string{"a", "b"},
+		Grid:  [2]int{1, 2},
+	}
+	check(inspectToText(&val) == "name: \"crate\"\n"+
+		"state: ModeIdle of [ModeIdle] ModeRun\n"+
+		"alive: false\n"+
+		"stats: \n"+
+		"  speed: 2.5\n"+
+		"  level: 3\n"+
+		"tags: 2 items\n"+
+		"  [0]: \"a\"\n"+
+		"  [1]: \"b\"\n"+
+		"grid: 2 items (fixed)\n"+
+		"  [0]: 1\n"+
+		"  [1]: 2\n"+
+		"mana: 0 items\n")
+	check(!inspectEdit(&val, "name", "select", 0, ""))
+	check(inspectEdit(&val, "name", "set", 0, "barrel"))
+	check(val.Name == "barrel")
+	check(inspectEdit(&val, "state", "select", 0, "ModeRun"))
+	check(val.State == foo.ModeRun)
+	check(!inspectEdit(&val, "state", "select", 0, "ModeRun"))
+	check(inspectEdit(&val, "alive", "set", 1, ""))
+	check(val.Alive)
+	check(inspectEdit(&val, "stats.speed", "set", 50, ""))
+	check(val.Stats.Speed == 10) // Clamped to the `max` attrib
+	check(inspectEdit(&val, "stats.level", "set", -4, ""))
+	check(val.Stats.Level == -4)
+	check(inspectEdit(&val, "tags", "add", 0, ""))
+	check(len(val.Tags) == 3 && val.Tags[2] == "")
+	check(inspectEdit(&val, "tags[0]", "down", 0, ""))
+	check(val.Tags[0] == "b" && val.Tags[1] == "a")
+	check(inspectEdit(&val, "tags[2]", "remove", 0, ""))
+	check(len(val.Tags) == 2)
+	check(inspectEdit(&val, "grid[1]", "up", 0, ""))
+	check(val.Grid[0] == 2 && val.Grid[1] == 1)
+	check(!inspectEdit(&val, "grid[0]", "remove", 0, ""))
+	check(!inspectEdit(&val, "grid", "add", 0, ""))
+	check(inspectEdit(&val, "mana", "add", 0, ""))
+	check(inspectEdit(&val, "mana[0]", "set", 500, ""))
+	check(val.Mana[0] == 100)
 }
 
 //
@@ -897,16 +1649,25 @@ func main() {
 	testStruct()
 	testMethod()
 	testGenerics()
+	testEquality()
+	testAnonymousStructs()
 	testLambdas()
 	testArrays()
 	testSlices()
 	testSeqs()
 	testGlobalVariables()
+	testConstants()
 	testImports()
 	testExterns()
 	testConversions()
 	testMeta()
+	testRegistration()
+	testJSON()
+	testBinary()
+	testEnums()
+	testLayout()
 	testDefaults()
+	testInspect()
 	testStrings()
 	testDefer()
 }
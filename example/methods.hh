@@ -0,0 +1,50 @@
+#pragma once
+
+#include <cstring>
+#include <type_traits>
+
+struct InspectorMethodAttribs {
+  const char *name;
+  const char *label = "";
+};
+
+#define GX_METHOD_ATTRIBS InspectorMethodAttribs
+
+// Like an inspector button, calls the reflected method named `name` if it takes
+// no arguments, returning whether there is one
+bool callMethod(auto *val, const char *name) {
+  auto called = false;
+  forEachMethod(*val, [&](auto methodTag, auto &&call) {
+    if constexpr (std::is_invocable_v<decltype(call)>) {
+      if (!called && std::strcmp(methodTag.attribs.name, name) == 0) {
+        call();
+        called = true;
+      }
+    }
+  });
+  return called;
+}
+
+// Like `callMethod`, but for methods taking a number, such as from a slider
+bool callMethodWithNumber(auto *val, const char *name, float arg) {
+  auto called = false;
+  forEachMethod(*val, [&](auto methodTag, auto &&call) {
+    if constexpr (std::is_invocable_v<decltype(call), float>) {
+      if (!called && std::strcmp(methodTag.attribs.name, name) == 0) {
+        call(arg);
+        called = true;
+      }
+    }
+  });
+  return called;
+}
+
+const char *methodLabels(auto *val) {
+  static char labels[256];
+  labels[0] = '\0';
+  forEachMethod(*val, [&](auto methodTag, auto &&call) {
+    std::strcat(labels, methodTag.attribs.label[0] ? methodTag.attribs.label : methodTag.attribs.name);
+    std::strcat(labels, ";");
+  });
+  return labels;
+}
@@ -0,0 +1,32 @@
+#pragma once
+
+#include <cstring>
+#include <new>
+#include <utility>
+
+namespace gx {
+struct StructLayout;
+template<typename T>
+struct Layout;
+template<typename T>
+void migrate(const StructLayout &oldLayout, void *oldBytes, T &newValue);
+}
+
+// Simulates a code reload, with `old` left in memory by the previous code
+template<typename Old, typename New>
+void migrateFromOld(Old old, New *newValue) {
+  alignas(Old) unsigned char bytes[sizeof(Old)];
+  new (bytes) Old(std::move(old));
+  gx::migrate(gx::Layout<Old>::layout, bytes, *newValue);
+}
+
+template<typename T>
+const char *layoutDefault(T *, const char *name) {
+  auto &layout = gx::Layout<T>::layout;
+  for (auto i = 0; i < layout.numFields; ++i) {
+    if (std::strcmp(layout.fields[i].name, name) == 0) {
+      return layout.fields[i].defaultValue;
+    }
+  }
+  return nullptr;
+}
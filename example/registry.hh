@@ -0,0 +1,29 @@
+#pragma once
+
+#include <cstring>
+
+// Collects type names by category as the program starts, like an engine's
+// component or system lists
+struct TypeRegistry {
+  const char *names[16];
+  int count = 0;
+};
+
+inline TypeRegistry componentTypes, systemTypes;
+
+inline bool registerType(TypeRegistry &registry, const char *name) {
+  registry.names[registry.count++] = name;
+  return true;
+}
+
+inline bool isRegistered(TypeRegistry *registry, const char *name) {
+  for (auto i = 0; i < registry->count; ++i) {
+    if (std::strcmp(registry->names[i], name) == 0) {
+      return true;
+    }
+  }
+  return false;
+}
+
+#define RegisterComponent(T) inline const bool T##ComponentRegistered = registerType(componentTypes, #T)
+#define RegisterSystem(T) inline const bool T##SystemRegistered = registerType(systemTypes, #T)
@@ -1,6 +1,10 @@
 struct SumFieldsAttribs {
   const char *name;
   bool twice = false;
+  int scale = 1;
+  const char *label = "";
+  double min = 0;
+  double max = 0;
 };
 
 #define GX_FIELD_ATTRIBS SumFieldsAttribs
@@ -9,10 +13,26 @@ int sumFields(auto &val) {
   auto sum = 0;
   forEachField(val, [&](auto fieldTag, auto &fieldVal) {
     if constexpr (fieldTag.attribs.twice) {
-      sum += 2 * fieldVal;
+      sum += 2 * fieldTag.attribs.scale * fieldVal;
     } else {
-      sum += fieldVal;
+      sum += fieldTag.attribs.scale * fieldVal;
     }
   });
   return sum;
 }
+
+const char *firstFieldLabel(auto &val) {
+  const char *label = "";
+  forEachField(val, [&](auto fieldTag, auto &fieldVal) {
+    if (label[0] == '\0') {
+      label = fieldTag.attribs.label;
+    }
+  });
+  return label;
+}
+
+void resetFields(auto *val) {
+  forEachField(*val, [&](auto fieldTag, auto &fieldVal) {
+    fieldVal = fieldTag.defaultValue();
+  });
+}
@@ -0,0 +1,39 @@
+#pragma once
+
+#include <cstdint>
+#include <type_traits>
+
+struct InspectorTypeAttribs {
+  bool editor_hidden = false;
+  const char *category = "";
+};
+
+#define GX_TYPE_ATTRIBS InspectorTypeAttribs
+
+namespace gx {
+template<typename T>
+struct TypeInfo;
+}
+
+template<typename T>
+using TypeInfoOf = gx::TypeInfo<std::remove_cvref_t<T>>;
+
+const char *typeName(const auto &val) {
+  return TypeInfoOf<decltype(val)>::name;
+}
+
+const char *typePackagePath(const auto &val) {
+  return TypeInfoOf<decltype(val)>::packagePath;
+}
+
+std::uint64_t typeID(const auto &val) {
+  return TypeInfoOf<decltype(val)>::id;
+}
+
+bool typeEditorHidden(const auto &val) {
+  return TypeInfoOf<decltype(val)>::attribs.editor_hidden;
+}
+
+const char *typeCategory(const auto &val) {
+  return TypeInfoOf<decltype(val)>::attribs.category;
+}
@@ -3,15 +3,24 @@ package main
 import (
 	"bytes"
 	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/parser"
 	"go/token"
 	"go/types"
+	"hash/fnv"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,10 +34,49 @@ type Target int
 const (
 	CPP Target = iota
 	GLSL
+	WGSL
 )
 
+type ShaderOutput struct {
+	shader        string
+	stage         string
+	profile       string
+	pos           token.Position
+	variant       []ShaderVariantValue
+	params        map[string][]ShaderParam // Declared variables by storage class
+	workgroupSize [3]int
+	output        *strings.Builder
+}
+
+type ShaderParam struct {
+	name    string
+	typ     string
+	binding int // From `shaderBindings`, or -1 if unbound
+}
+
+type ShaderVariantValue struct {
+	name  string
+	value constant.Value
+}
+
+var glslProfiles = []string{"100", "300es", "330", "430"}
+
+// Compute shader built-ins by GLSL name, with the WGSL built-in and type that
+// WGSL entry points copy to private variables
+var gxslComputeBuiltins = []struct{ glsl, wgsl, wgslType string }{
+	{"gl_GlobalInvocationID", "global_invocation_id", "vec3<u32>"},
+	{"gl_LocalInvocationID", "local_invocation_id", "vec3<u32>"},
+	{"gl_WorkGroupID", "workgroup_id", "vec3<u32>"},
+	{"gl_NumWorkGroups", "num_workgroups", "vec3<u32>"},
+	{"gl_LocalInvocationIndex", "local_invocation_index", "u32"},
+}
+
 type Compiler struct {
 	mainPkgPath string
+	buildTags   string
+	glslProfile string
+	emitWGSL    bool
+	emitGXSLCPP bool
 
 	fileSet *token.FileSet
 	types   *types.Info
@@ -39,12 +87,32 @@ type Compiler struct {
 	fieldIndices    map[*types.Var]int
 	methodRenames   map[types.Object]string
 	methodFieldTags map[types.Object]string
+	initFuncNames   map[*ast.FuncDecl]string
+	constValueExprs map[types.Object]ast.Expr
+	enumValues      map[types.Object][]*types.Const // Constants of each enum type, in declaration order
+	constOverrides  map[types.Object]constant.Value // Values of shader variant constants being output
+	arrayLenExprs   map[*types.Array]ast.Expr       // Length expressions of array types, to check for variant constants
+	storageBuffers  map[*types.Var]bool             // Storage buffer parameters of compute shaders
+	wgslHelperDefns map[string]string               // WGSL helper functions used by the shader being output, by name
 	genTypeExprs    map[Target]map[types.Type]string
 	genTypeDecls    map[*ast.TypeSpec]string
 	genTypeDefns    map[Target]map[*ast.TypeSpec]string
 	genTypeMetas    map[*ast.TypeSpec]string
+	genMethodMetas  map[*ast.TypeSpec]string
 	genFuncDecls    map[Target]map[*ast.FuncDecl]string
 
+	fieldAttribsType *types.Named            // Go declaration of `GX_FIELD_ATTRIBS`, if any, to check attribs against
+	typeAttribsType  *types.Named            // Likewise for `GX_TYPE_ATTRIBS` and `//gx:attribs` directives
+	typeAttribs      map[types.Object]string // `//gx:attribs` directives of struct types
+	layoutTypes      map[types.Object]bool   // Structs with `//gx:layout` to generate `gx::Layout` for
+	fieldDefaults    map[*types.Var]ast.Expr // Type-checked `default` field tags
+
+	methodAttribsType *types.Named                       // Likewise for `GX_METHOD_ATTRIBS` and `//gx:reflect` directives
+	reflectedMethods  map[types.Object][]reflectedMethod // Of each receiver type, in declaration order
+
+	anonStructTypeSpecs    map[*ast.StructType]*ast.TypeSpec
+	anonStructTypeSpecList []*ast.TypeSpec
+
 	genIdentifierCount int
 
 	indent     int
@@ -54,7 +122,9 @@ type Compiler struct {
 	output      *strings.Builder
 	outputCC    *strings.Builder
 	outputHH    *strings.Builder
-	outputGLSLs map[string]*strings.Builder
+	outputGLSLs map[string]*ShaderOutput
+	outputWGSLs map[string]*ShaderOutput
+	outputGos   map[string]string // Generated Go sources by path, empty to remove a stale one
 }
 
 //
@@ -112,7 +182,40 @@ func (c *Compiler) generateIdentifier(prefix string) string {
 // Types
 //
 
+// Whether `obj` is a named integer type, which is an enum if it has constants
+func isEnumCandidate(obj types.Object) bool {
+	typeName, ok := obj.(*types.TypeName)
+	if !ok || typeName.IsAlias() {
+		return false
+	}
+	basic, ok := typeName.Type().Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+func (c *Compiler) isEnumType(typ types.Type) bool {
+	if named, ok := typ.(*types.Named); ok {
+		_, ok := c.enumValues[named.Obj()]
+		return ok
+	}
+	return false
+}
+
+// C++ types of the sized integers that shaders don't have
+var cppSizedInts = map[types.BasicKind]string{
+	types.Int8:   "std::int8_t",
+	types.Int16:  "std::int16_t",
+	types.Int64:  "std::int64_t",
+	types.Uint8:  "std::uint8_t",
+	types.Uint16: "std::uint16_t",
+	types.Uint64: "std::uint64_t",
+}
+
 func (c *Compiler) genTypeExpr(typ types.Type, pos token.Pos) string {
+	// Array lengths are fixed when type-checking, so they can't follow variant
+	// constants. Checked before the cache, which is shared across variants.
+	if array, ok := typ.(*types.Array); ok && c.dependsOnOverrides(c.arrayLenExprs[array]) {
+		c.errorf(c.arrayLenExprs[array].Pos(), "unsupported constant expression for shader variant")
+	}
 	if result, ok := c.genTypeExprs[c.target][typ]; ok {
 		return result
 	}
@@ -124,24 +227,62 @@ func (c *Compiler) genTypeExpr(typ types.Type, pos token.Pos) string {
 		case types.Bool, types.UntypedBool:
 			builder.WriteString("bool")
 		case types.Int, types.UntypedInt:
-			switch c.target {
-			case CPP:
+			switch {
+			case c.target == CPP:
 				builder.WriteString("int")
-			case GLSL:
-				builder.WriteString("float")
+			case !c.shaderInts():
+				builder.WriteString("float") // GLSL 100 integers are too limited to be useful
+			case c.target == GLSL:
+				builder.WriteString("int")
+			case c.target == WGSL:
+				builder.WriteString("i32")
 			}
 		case types.Float32, types.Float64, types.UntypedFloat:
-			builder.WriteString("float")
+			switch c.target {
+			case CPP, GLSL:
+				builder.WriteString("float")
+			case WGSL:
+				builder.WriteString("f32")
+			}
 		case types.Uint:
-			builder.WriteString("gx::uint")
-		case types.Uint8:
-			builder.WriteString("std::uint8_t")
-		case types.Uint16:
-			builder.WriteString("std::uint16_t")
+			switch {
+			case c.target == CPP:
+				builder.WriteString("gx::uint")
+			case !c.shaderInts():
+				c.errorf(pos, "uint needs GLSL profile 300es or later")
+			case c.target == GLSL:
+				builder.WriteString("uint")
+			case c.target == WGSL:
+				builder.WriteString("u32")
+			}
+		case types.Int32:
+			switch {
+			case c.target == CPP:
+				builder.WriteString("std::int32_t")
+			case !c.shaderInts():
+				c.errorf(pos, "int32 needs GLSL profile 300es or later")
+			case c.target == GLSL:
+				builder.WriteString("int")
+			case c.target == WGSL:
+				builder.WriteString("i32")
+			}
 		case types.Uint32:
-			builder.WriteString("std::uint32_t")
-		case types.Uint64:
-			builder.WriteString("std::uint64_t")
+			switch {
+			case c.target == CPP:
+				builder.WriteString("std::uint32_t")
+			case !c.shaderInts():
+				c.errorf(pos, "uint32 needs GLSL profile 300es or later")
+			case c.target == GLSL:
+				builder.WriteString("uint")
+			case c.target == WGSL:
+				builder.WriteString("u32")
+			}
+		case types.Int8, types.Int16, types.Int64, types.Uint8, types.Uint16, types.Uint64:
+			if c.target != CPP {
+				c.errorf(pos, "%s not supported in GXSL", typ.String())
+				break
+			}
+			builder.WriteString(cppSizedInts[typ.Kind()])
 		case types.String:
 			builder.WriteString("gx::String")
 		default:
@@ -176,7 +317,11 @@ func (c *Compiler) genTypeExpr(typ types.Type, pos token.Pos) string {
 		builder.WriteString(typ.Obj().Name())
 		builder.WriteByte(' ')
 	case *types.Array:
-		builder.WriteString("gx::Array<")
+		if c.target == WGSL {
+			builder.WriteString("array<")
+		} else {
+			builder.WriteString("gx::Array<")
+		}
 		builder.WriteString(trimFinalSpace(c.genTypeExpr(typ.Elem(), pos)))
 		builder.WriteString(", ")
 		builder.WriteString(strconv.FormatInt(typ.Len(), 10))
@@ -187,6 +332,23 @@ func (c *Compiler) genTypeExpr(typ types.Type, pos token.Pos) string {
 		builder.WriteString(trimFinalSpace(c.genTypeExpr(typ.Elem(), pos)))
 		builder.WriteString(">")
 		builder.WriteByte(' ')
+	case *types.Struct:
+		if c.target != CPP {
+			c.errorf(pos, "anonymous structs not supported in GXSL")
+			break
+		}
+		found := false
+		for _, anonTypeSpec := range c.anonStructTypeSpecList {
+			if types.Identical(typ, c.types.TypeOf(anonTypeSpec.Type)) {
+				builder.WriteString(anonTypeSpec.Name.String())
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.errorf(pos, "no declaration generated for anonymous struct %s", typ.String())
+		}
+		builder.WriteByte(' ')
 	default:
 		c.errorf(pos, "%s not supported", typ.String())
 	}
@@ -223,11 +385,22 @@ func (c *Compiler) genTypeDecl(typeSpec *ast.TypeSpec) string {
 		// Empty -- only used as generic constraint during typecheck
 		builder = &strings.Builder{}
 	default:
-		builder.WriteString("using ")
-		builder.WriteString(typeSpec.Name.String())
-		builder.WriteString(" = ")
 		typ := c.types.TypeOf(typeSpec.Type)
-		builder.WriteString(trimFinalSpace(c.genTypeExpr(typ, typeSpec.Type.Pos())))
+		if _, ok := c.enumValues[c.types.Defs[typeSpec.Name]]; ok {
+			base := "gx::Enum<" + trimFinalSpace(c.genTypeExpr(typ.Underlying(), typeSpec.Type.Pos())) + ">"
+			builder.WriteString("struct ")
+			builder.WriteString(typeSpec.Name.String())
+			builder.WriteString(" : ")
+			builder.WriteString(base)
+			builder.WriteString(" {\n  using ")
+			builder.WriteString(base)
+			builder.WriteString("::Enum;\n}")
+		} else {
+			builder.WriteString("using ")
+			builder.WriteString(typeSpec.Name.String())
+			builder.WriteString(" = ")
+			builder.WriteString(trimFinalSpace(c.genTypeExpr(typ, typeSpec.Type.Pos())))
+		}
 	}
 
 	result := builder.String()
@@ -247,24 +420,37 @@ func (c *Compiler) genTypeDefn(typeSpec *ast.TypeSpec) string {
 		builder.WriteString(" {\n")
 		for _, field := range typ.Fields.List {
 			if fieldType := c.types.TypeOf(field.Type); fieldType != nil {
-				var defaultVal string
-				if tag := field.Tag; tag != nil && tag.Kind == token.STRING {
-					unquoted, _ := strconv.Unquote(tag.Value)
-					defaultVal = reflect.StructTag(unquoted).Get("default")
-				}
 				typeExpr := c.genTypeExpr(fieldType, field.Type.Pos())
 				for _, fieldName := range field.Names {
+					if c.target == WGSL {
+						builder.WriteString("  ")
+						builder.WriteString(fieldName.String())
+						builder.WriteString(": ")
+						builder.WriteString(trimFinalSpace(typeExpr))
+						builder.WriteString(",\n")
+						continue
+					}
 					builder.WriteString("  ")
 					builder.WriteString(typeExpr)
 					builder.WriteString(fieldName.String())
-					if defaultVal != "" {
-						builder.WriteString(" = ")
-						builder.WriteString(defaultVal)
+					if c.target == CPP {
+						// Shaders apply defaults in struct literals instead
+						if defaultVal := c.genFieldDefault(c.types.Defs[fieldName].(*types.Var)); defaultVal != "" {
+							builder.WriteString(" = ")
+							builder.WriteString(defaultVal)
+						}
 					}
 					builder.WriteString(";\n")
 				}
 			}
 		}
+		if c.target == CPP {
+			if typeSpec.TypeParams != nil || types.Comparable(c.types.TypeOf(typeSpec.Name)) {
+				builder.WriteString("\n  bool operator==(const ")
+				builder.WriteString(typeSpec.Name.String())
+				builder.WriteString(" &) const = default;\n")
+			}
+		}
 		builder.WriteByte('}')
 	case *ast.InterfaceType:
 		// Empty -- only used as generic constraint during typecheck
@@ -277,63 +463,595 @@ func (c *Compiler) genTypeDefn(typeSpec *ast.TypeSpec) string {
 	return result
 }
 
-func (c *Compiler) genTypeMeta(typeSpec *ast.TypeSpec) string {
-	if result, ok := c.genTypeMetas[typeSpec]; ok {
-		return result
+// Parses and type-checks the `default` tags of a struct's fields. Defaults
+// must be constants or composite literals of constants assignable to the field.
+func (c *Compiler) collectFieldDefaults(pkg *types.Package, structType *ast.StructType) {
+	var isConstant func(expr ast.Expr) bool
+	isConstant = func(expr ast.Expr) bool {
+		switch expr := expr.(type) {
+		case *ast.ParenExpr:
+			return isConstant(expr.X)
+		case *ast.CompositeLit:
+			for _, elt := range expr.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				if !isConstant(elt) {
+					return false
+				}
+			}
+			return true
+		}
+		return c.types.Types[expr].Value != nil
 	}
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil || field.Tag.Kind != token.STRING {
+			continue
+		}
+		unquoted, _ := strconv.Unquote(field.Tag.Value)
+		src, ok := reflect.StructTag(unquoted).Lookup("default")
+		if !ok {
+			continue
+		}
+		tagPos := field.Tag.Pos()
+		expr, err := parser.ParseExprFrom(c.fileSet, c.fileSet.Position(tagPos).Filename, src, 0)
+		if err != nil {
+			c.errorf(tagPos, "malformed default tag: %s", src)
+			continue
+		}
+		if err := types.CheckExpr(c.fileSet, pkg, tagPos, expr, c.types); err != nil {
+			if typeErr, ok := err.(types.Error); ok {
+				err = errors.New(typeErr.Msg)
+			}
+			c.errorf(tagPos, "invalid default value %s: %s", src, err)
+			continue
+		}
+		fieldType := c.types.TypeOf(field.Type)
+		tv := c.types.Types[expr]
+		if !isConstant(expr) {
+			c.errorf(tagPos, "default value %s must be a constant or a composite literal of constants", src)
+			continue
+		}
+		if !types.AssignableTo(tv.Type, fieldType) {
+			c.errorf(tagPos, "cannot use %s (%s) as %s default value", src, tv.Type, fieldType)
+			continue
+		}
+		if tv.Value != nil {
+			if _, ok := fieldType.Underlying().(*types.Basic); ok && !c.constantFits(tv.Value, fieldType) {
+				c.errorf(tagPos, "default value %s is not representable by %s", src, fieldType)
+				continue
+			}
+			c.types.Types[expr] = types.TypeAndValue{Type: fieldType, Value: tv.Value} // Convert untyped constants
+		}
+		for _, fieldName := range field.Names {
+			c.fieldDefaults[c.types.Defs[fieldName].(*types.Var)] = expr
+		}
+	}
+}
 
-	builder := &strings.Builder{}
-	switch typ := typeSpec.Type.(type) {
-	case *ast.StructType:
-		typeParamsBuilder := &strings.Builder{}
-		if typeSpec.TypeParams != nil {
-			for i, typeParam := range typeSpec.TypeParams.List {
-				for j, name := range typeParam.Names {
-					if i > 0 || j > 0 {
-						typeParamsBuilder.WriteString(", ")
+// Output expression for the `default` tag of a field, if it has one
+func (c *Compiler) genFieldDefault(field *types.Var) string {
+	expr, ok := c.fieldDefaults[field]
+	if !ok {
+		return ""
+	}
+	output, indent := c.output, c.indent
+	c.output, c.indent = &strings.Builder{}, 0
+	c.writeExpr(expr)
+	result := c.output.String()
+	c.output, c.indent = output, indent
+	return result
+}
+
+// Name of the Go source generated in each package of the main module with
+// `NewT()` constructors applying `default` tags, so that code built by the Go
+// toolchain sees the same values as the output. These are meant to be
+// committed. Imported modules are left alone since they may be read-only. They're constrained to
+// `!gx` builds and gx always loads packages with the `gx` tag, so a stale one
+// never stops gx from regenerating it. gx code gets defaults from `T{}`.
+const goDefaultsFileName = "gx_defaults.go"
+
+const goDefaultsHeader = "// Code generated by gx from `default` field tags. DO NOT EDIT.\n"
+
+func (c *Compiler) genGoDefaults(pkgs []*packages.Package) {
+	// Find main module packages of gx sources and their struct types with defaults
+	gxPkgs := map[*types.Package]bool{}
+	for _, pkg := range pkgs {
+		if pkg.Module == nil || !pkg.Module.Main {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			if strings.HasSuffix(c.fileSet.Position(file.Package).Filename, ".gx.go") {
+				gxPkgs[pkg.Types] = true
+			}
+		}
+	}
+	hasDefaults := map[*types.TypeName]bool{}
+	var checkDefaults func(typeName *types.TypeName) bool
+	checkDefaults = func(typeName *types.TypeName) bool {
+		if result, ok := hasDefaults[typeName]; ok {
+			return result
+		}
+		hasDefaults[typeName] = false // Break cycles through pointers
+		named, ok := typeName.Type().(*types.Named)
+		if !ok || named.TypeParams() != nil || !gxPkgs[typeName.Pkg()] {
+			return false
+		}
+		structType, ok := named.Underlying().(*types.Struct)
+		if !ok {
+			return false
+		}
+		for i := 0; i < structType.NumFields(); i++ {
+			field := structType.Field(i)
+			if _, ok := c.fieldDefaults[field]; ok {
+				hasDefaults[typeName] = true
+			} else if fieldNamed, ok := field.Type().(*types.Named); ok && checkDefaults(fieldNamed.Obj()) {
+				hasDefaults[typeName] = true
+			}
+		}
+		return hasDefaults[typeName]
+	}
+	constructorName := func(typeName *types.TypeName) string {
+		if typeName.Exported() {
+			return "New" + typeName.Name()
+		}
+		return "new" + strings.ToUpper(typeName.Name()[:1]) + typeName.Name()[1:]
+	}
+
+	for _, pkg := range pkgs {
+		if !gxPkgs[pkg.Types] {
+			continue
+		}
+		dir := filepath.Dir(c.fileSet.Position(pkg.Syntax[0].Package).Filename)
+		path := filepath.Join(dir, goDefaultsFileName)
+		existing, err := os.ReadFile(path)
+		generated := err == nil && strings.HasPrefix(string(existing), goDefaultsHeader)
+		for _, file := range pkg.Syntax {
+			if filepath.Base(c.fileSet.Position(file.Package).Filename) == goDefaultsFileName && !generated {
+				c.errorf(file.Package, "%s is reserved for constructors generated by gx", goDefaultsFileName)
+			}
+		}
+		if err == nil && !generated {
+			continue // Not ours to overwrite
+		}
+
+		// Constructors in declaration order
+		imports := map[string]string{} // Import paths by name
+		body := &strings.Builder{}
+		scope := pkg.Types.Scope()
+		names := scope.Names()
+		sort.Slice(names, func(i, j int) bool {
+			return scope.Lookup(names[i]).Pos() < scope.Lookup(names[j]).Pos()
+		})
+		for _, name := range names {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() || !checkDefaults(typeName) {
+				continue
+			}
+			funcName := constructorName(typeName)
+			if scope.Lookup(funcName) != nil {
+				continue // Declared by hand
+			}
+			var elts []string
+			structType := typeName.Type().Underlying().(*types.Struct)
+			for i := 0; i < structType.NumFields(); i++ {
+				field := structType.Field(i)
+				if expr, ok := c.fieldDefaults[field]; ok {
+					ast.Inspect(expr, func(node ast.Node) bool {
+						if ident, ok := node.(*ast.Ident); ok {
+							if pkgName, ok := c.types.Uses[ident].(*types.PkgName); ok {
+								imports[pkgName.Name()] = pkgName.Imported().Path()
+							}
+						}
+						return true
+					})
+					src := &strings.Builder{}
+					format.Node(src, c.fileSet, expr)
+					elts = append(elts, field.Name()+": "+src.String())
+				} else if fieldNamed, ok := field.Type().(*types.Named); ok && checkDefaults(fieldNamed.Obj()) {
+					fieldPkg := fieldNamed.Obj().Pkg()
+					qualifier := ""
+					if fieldPkg != pkg.Types {
+						qualifier = fieldPkg.Name() + "."
+						imports[fieldPkg.Name()] = fieldPkg.Path()
 					}
-					typeParamsBuilder.WriteString("typename ")
-					typeParamsBuilder.WriteString(name.String())
+					elts = append(elts, field.Name()+": "+qualifier+constructorName(fieldNamed.Obj())+"()")
+				}
+			}
+			fmt.Fprintf(body, "\n// %s returns a new %s with its `default` field tags applied\n", funcName, name)
+			fmt.Fprintf(body, "func %s() %s {\n\treturn %s{\n", funcName, name, name)
+			for _, elt := range elts {
+				fmt.Fprintf(body, "\t\t%s,\n", elt)
+			}
+			body.WriteString("\t}\n}\n")
+		}
+		if body.Len() == 0 {
+			if generated {
+				c.outputGos[path] = ""
+			}
+			continue
+		}
+
+		builder := &strings.Builder{}
+		builder.WriteString(goDefaultsHeader)
+		builder.WriteString("\n//go:build !gx\n")
+		fmt.Fprintf(builder, "\npackage %s\n", pkg.Types.Name())
+		if len(imports) > 0 {
+			builder.WriteString("\nimport (\n")
+			importNames := make([]string, 0, len(imports))
+			for name := range imports {
+				importNames = append(importNames, name)
+			}
+			sort.Strings(importNames)
+			for _, name := range importNames {
+				if path := imports[name]; filepath.Base(path) == name {
+					fmt.Fprintf(builder, "\t%q\n", path)
+				} else {
+					fmt.Fprintf(builder, "\t%s %q\n", name, path)
+				}
+			}
+			builder.WriteString(")\n")
+		}
+		builder.WriteString(body.String())
+		formatted, err := format.Source([]byte(builder.String()))
+		if err != nil {
+			c.errorf(pkg.Syntax[0].Package, "internal error: malformed generated defaults: %s", err)
+			continue
+		}
+		c.outputGos[path] = string(formatted)
+	}
+}
+
+type FieldAttrib struct {
+	key   string
+	kind  string // "flag", "number", "string" or "ident"
+	value string
+}
+
+var fieldAttribNumberRe = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+var fieldAttribIntegerRe = regexp.MustCompile(`^[-+]?\d+$`)
+
+// Parse an `attribs` tag such as `twice,min=0,max=100,label='Move speed'`. Keys
+// without values are flags. Values are numbers, quoted strings or identifiers.
+func parseFieldAttribs(tag string) ([]FieldAttrib, error) {
+	var attribs []FieldAttrib
+	i := 0
+	isIdentStart := func(b byte) bool {
+		return b == '_' || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+	}
+	isIdentPart := func(b byte) bool {
+		return isIdentStart(b) || ('0' <= b && b <= '9')
+	}
+	readWhile := func(pred func(b byte) bool) string {
+		start := i
+		for i < len(tag) && pred(tag[i]) {
+			i++
+		}
+		return tag[start:i]
+	}
+	skipSpaces := func() {
+		readWhile(func(b byte) bool { return b == ' ' })
+	}
+	for {
+		skipSpaces()
+		if i >= len(tag) || !isIdentStart(tag[i]) {
+			return nil, fmt.Errorf("expected an attribute name at '%s'", tag[i:])
+		}
+		attrib := FieldAttrib{key: readWhile(isIdentPart), kind: "flag"}
+		skipSpaces()
+		if i < len(tag) && tag[i] == '=' {
+			i++
+			skipSpaces()
+			switch {
+			case i >= len(tag):
+				return nil, fmt.Errorf("missing value for %s", attrib.key)
+			case tag[i] == '\'' || tag[i] == '"':
+				end := strings.IndexByte(tag[i+1:], tag[i])
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated string value for %s", attrib.key)
+				}
+				attrib.kind, attrib.value = "string", tag[i+1:i+1+end]
+				i += end + 2
+			case isIdentStart(tag[i]):
+				attrib.kind, attrib.value = "ident", readWhile(isIdentPart)
+			default:
+				attrib.kind, attrib.value = "number", readWhile(func(b byte) bool { return b != ',' && b != ' ' })
+				if !fieldAttribNumberRe.MatchString(attrib.value) {
+					return nil, fmt.Errorf("invalid value '%s' for %s", attrib.value, attrib.key)
+				}
+				attrib.value = strings.TrimPrefix(attrib.value, "+")
+			}
+			skipSpaces()
+		}
+		for _, prev := range attribs {
+			if prev.key == attrib.key {
+				return nil, fmt.Errorf("duplicate attribute %s", attrib.key)
+			}
+		}
+		attribs = append(attribs, attrib)
+		if i >= len(tag) {
+			return attribs, nil
+		}
+		if tag[i] != ',' {
+			return nil, fmt.Errorf("expected ',' after %s", attrib.key)
+		}
+		i++
+	}
+}
+
+// Checks attribs against the fields of `schema`, the Go declaration of their
+// C++ struct, and sorts them into field order. `kind` is "field" or "type".
+func (c *Compiler) checkAttribs(schema *types.Named, kind string, attribs []FieldAttrib, pos token.Pos) ([]FieldAttrib, bool) {
+	if schema == nil {
+		return attribs, true
+	}
+	structType := schema.Underlying().(*types.Struct)
+	fieldIndices := map[string]int{}
+	for i := range attribs {
+		attrib := &attribs[i]
+		var field *types.Var
+		for fieldIndex, nFields := 0, structType.NumFields(); fieldIndex < nFields; fieldIndex++ {
+			name := structType.Field(fieldIndex).Name()
+			if ext, ok := c.externs[CPP][structType.Field(fieldIndex)]; ok {
+				name = ext
+			}
+			if name == attrib.key {
+				field = structType.Field(fieldIndex)
+				fieldIndices[attrib.key] = fieldIndex
+			}
+		}
+		if field == nil {
+			c.errorf(pos, "%s has no %s attrib %s", schema.Obj().Name(), kind, attrib.key)
+			return nil, false
+		}
+		valid := true
+		if basic, ok := field.Type().Underlying().(*types.Basic); ok {
+			switch {
+			case basic.Info()&types.IsBoolean != 0:
+				valid = attrib.kind == "flag" || (attrib.kind == "ident" && (attrib.value == "true" || attrib.value == "false"))
+			case basic.Info()&types.IsInteger != 0:
+				valid = attrib.kind == "number" && fieldAttribIntegerRe.MatchString(attrib.value)
+			case basic.Info()&types.IsFloat != 0:
+				valid = attrib.kind == "number"
+			case basic.Info()&types.IsString != 0:
+				valid = attrib.kind == "string" || attrib.kind == "ident"
+				if attrib.kind == "ident" { // Identifiers name things, such as enum types in editor hints
+					attrib.kind = "string"
 				}
 			}
 		}
-		typeParams := typeParamsBuilder.String()
-		typeExprBuilder := &strings.Builder{}
-		typeExprBuilder.WriteString(typeSpec.Name.String())
-		if typeSpec.TypeParams != nil {
-			typeExprBuilder.WriteString("<")
-			for i, typeParam := range typeSpec.TypeParams.List {
-				for j, name := range typeParam.Names {
-					if i > 0 || j > 0 {
-						typeExprBuilder.WriteString(", ")
+		if !valid {
+			value := attrib.value
+			if attrib.kind == "flag" {
+				value = "(no value)"
+			}
+			c.errorf(pos, "%s attrib %s must be of type %s, not %s", kind, attrib.key, field.Type(), value)
+			return nil, false
+		}
+	}
+	slices.SortStableFunc(attribs, func(a, b FieldAttrib) int {
+		return fieldIndices[a.key] - fieldIndices[b.key]
+	})
+	return attribs, true
+}
+
+// Describes the field names and types that binary serialization depends on.
+// Struct type names are left out so renaming a type keeps its data readable.
+// With `layout`, describes the C++ memory layout instead, so all fields are
+// included under their C++ names.
+func (c *Compiler) schemaString(typ types.Type, visiting map[*types.Named]bool, layout bool) string {
+	switch typ := typ.(type) {
+	case *types.Named:
+		if ext, ok := c.externs[CPP][typ.Obj()]; ok {
+			return ext
+		}
+		if visiting[typ] {
+			return typ.Obj().Name()
+		}
+		visiting[typ] = true
+		defer delete(visiting, typ)
+		return c.schemaString(typ.Underlying(), visiting, layout)
+	case *types.Struct:
+		builder := &strings.Builder{}
+		builder.WriteString("{")
+		for i := 0; i < typ.NumFields(); i++ {
+			if field := typ.Field(i); field.Exported() || layout {
+				name := lowerFirst(field.Name())
+				if layout {
+					name = field.Name()
+				} else if attribsTag := reflect.StructTag(typ.Tag(i)).Get("attribs"); attribsTag != "" {
+					attribs, _ := parseFieldAttribs(attribsTag)
+					for _, attrib := range attribs {
+						if attrib.key == "uppercase" && attrib.kind == "flag" {
+							name = field.Name()
+						}
+					}
+					for _, attrib := range attribs {
+						if attrib.key == "id" {
+							name += "@" + attrib.value // Explicit ids change how data is read
+						}
 					}
-					typeExprBuilder.WriteString(name.String())
 				}
+				builder.WriteString(name)
+				builder.WriteString(":")
+				builder.WriteString(c.schemaString(field.Type(), visiting, layout))
+				builder.WriteString(";")
+			}
+		}
+		builder.WriteString("}")
+		return builder.String()
+	case *types.Slice:
+		return "[]" + c.schemaString(typ.Elem(), visiting, layout)
+	case *types.Array:
+		return "[" + strconv.FormatInt(typ.Len(), 10) + "]" + c.schemaString(typ.Elem(), visiting, layout)
+	default:
+		return typ.String()
+	}
+}
+
+// Binary serialization keys fields by id rather than position so they can be
+// reordered. Ids are set with an `id` attrib, or are a hash of the field's
+// serialized name small enough to keep keys within two varint bytes. Ids below
+// 16 keep keys within one byte.
+const maxBinaryFieldID = 1<<29 - 1
+
+func binaryFieldID(name string, idAttrib *FieldAttrib) (int64, error) {
+	if idAttrib != nil {
+		id, err := strconv.ParseInt(idAttrib.value, 10, 64)
+		if idAttrib.kind != "number" || err != nil || id < 0 || id > maxBinaryFieldID {
+			return 0, fmt.Errorf("field attrib id must be an integer from 0 to %d", maxBinaryFieldID)
+		}
+		return id, nil
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	return int64(hash.Sum32() & (1<<11 - 1)), nil
+}
+
+// Instantiations of generic type `obj` with concrete type arguments, sorted by
+// name. Instantiations only inside generic code aren't included, and ones that
+// are the same type in C++ (such as with `float32` and `float64`) are merged.
+func (c *Compiler) typeInstances(obj types.Object) []types.Type {
+	var instances []types.Type
+	seen := map[string]bool{}
+	for _, instance := range c.types.Instances {
+		if named, ok := instance.Type.(*types.Named); ok && named.Origin().Obj() == obj && !containsTypeParams(named) {
+			if typeExpr := c.genTypeExpr(named, obj.Pos()); !seen[typeExpr] {
+				seen[typeExpr] = true
+				instances = append(instances, named)
+			}
+		}
+	}
+	slices.SortFunc(instances, func(a, b types.Type) int {
+		return strings.Compare(types.TypeString(a, nil), types.TypeString(b, nil))
+	})
+	return instances
+}
+
+func containsTypeParams(typ types.Type) bool {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Pointer:
+		return containsTypeParams(typ.Elem())
+	case *types.Slice:
+		return containsTypeParams(typ.Elem())
+	case *types.Array:
+		return containsTypeParams(typ.Elem())
+	case *types.Named:
+		if typeArgs := typ.TypeArgs(); typeArgs != nil {
+			for i := 0; i < typeArgs.Len(); i++ {
+				if containsTypeParams(typeArgs.At(i)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Designated initializers like `.label = "Speed"` for attribs
+func genAttribInits(attribs []FieldAttrib) []string {
+	var inits []string
+	for _, attrib := range attribs {
+		value := attrib.value
+		switch attrib.kind {
+		case "flag":
+			value = "true"
+		case "string":
+			value = cStringLiteral(attrib.value)
+		}
+		inits = append(inits, "."+attrib.key+" = "+value)
+	}
+	return inits
+}
+
+// Template parameters like `typename T` and the type expression like
+// `Holder<T>` that meta specializations of a possibly generic type use
+func genMetaTypeParamsAndExpr(typeSpec *ast.TypeSpec) (string, string) {
+	typeParamsBuilder := &strings.Builder{}
+	if typeSpec.TypeParams != nil {
+		for i, typeParam := range typeSpec.TypeParams.List {
+			for j, name := range typeParam.Names {
+				if i > 0 || j > 0 {
+					typeParamsBuilder.WriteString(", ")
+				}
+				typeParamsBuilder.WriteString("typename ")
+				typeParamsBuilder.WriteString(name.String())
+			}
+		}
+	}
+	typeExprBuilder := &strings.Builder{}
+	typeExprBuilder.WriteString(typeSpec.Name.String())
+	if typeSpec.TypeParams != nil {
+		typeExprBuilder.WriteString("<")
+		for i, typeParam := range typeSpec.TypeParams.List {
+			for j, name := range typeParam.Names {
+				if i > 0 || j > 0 {
+					typeExprBuilder.WriteString(", ")
+				}
+				typeExprBuilder.WriteString(name.String())
 			}
-			typeExprBuilder.WriteString(">")
 		}
-		typeExpr := typeExprBuilder.String()
+		typeExprBuilder.WriteString(">")
+	}
+	return typeParamsBuilder.String(), typeExprBuilder.String()
+}
+
+func (c *Compiler) genTypeMeta(typeSpec *ast.TypeSpec) string {
+	if result, ok := c.genTypeMetas[typeSpec]; ok {
+		return result
+	}
+
+	builder := &strings.Builder{}
+	switch typ := typeSpec.Type.(type) {
+	case *ast.StructType:
+		typeParams, typeExpr := genMetaTypeParamsAndExpr(typeSpec)
 
 		// `gx::FieldTag` specializations
 		tagIndex := 0
+		binaryFieldNames := map[int64]string{}
 		for _, field := range typ.Fields.List {
 			if field.Type != nil {
 				for _, fieldName := range field.Names {
 					if fieldName.IsExported() {
 						uppercase := false
-						var attribs []string
+						var idAttrib *FieldAttrib
+						var attribs []FieldAttrib
 						if tag := field.Tag; tag != nil && tag.Kind == token.STRING {
 							unquoted, _ := strconv.Unquote(tag.Value)
 							if attribsTag := reflect.StructTag(unquoted).Get("attribs"); attribsTag != "" {
-								for _, attrib := range strings.Split(attribsTag, ",") {
-									if attrib == "uppercase" {
+								parsed, err := parseFieldAttribs(attribsTag)
+								if err != nil {
+									c.errorf(tag.Pos(), "malformed attribs tag: %s", err)
+								}
+								for _, attrib := range parsed {
+									switch {
+									case attrib.key == "uppercase" && attrib.kind == "flag":
 										uppercase = true
-									} else {
-										attribs = append(attribs, strings.TrimSpace(attrib))
+									case attrib.key == "name":
+										c.errorf(tag.Pos(), "field attrib name is set from the field's name")
+									case attrib.key == "id":
+										idAttrib = &attrib
+									default:
+										attribs = append(attribs, attrib)
 									}
 								}
+								attribs, _ = c.checkAttribs(c.fieldAttribsType, "field", attribs, tag.Pos())
 							}
 						}
+						name := lowerFirst(fieldName.String())
+						if uppercase {
+							name = fieldName.String()
+						}
+						binaryID, err := binaryFieldID(name, idAttrib)
+						if err != nil {
+							c.errorf(field.Tag.Pos(), "%s", err)
+						} else if other, ok := binaryFieldNames[binaryID]; ok {
+							c.errorf(fieldName.Pos(), "fields %s and %s have the same binary id %d, set another with `attribs:\"id=N\"`",
+								other, fieldName.String(), binaryID)
+						}
+						binaryFieldNames[binaryID] = fieldName.String()
 						builder.WriteString("template<")
 						builder.WriteString(typeParams)
 						builder.WriteString(">\nstruct gx::FieldTag<")
@@ -342,18 +1060,27 @@ func (c *Compiler) genTypeMeta(typeSpec *ast.TypeSpec) string {
 						builder.WriteString(strconv.Itoa(tagIndex))
 						builder.WriteString("> {\n")
 						builder.WriteString("  inline static constexpr gx::FieldAttribs attribs { .name = \"")
-						if uppercase {
-							builder.WriteString(fieldName.String())
-						} else {
-							builder.WriteString(lowerFirst(fieldName.String()))
-						}
+						builder.WriteString(name)
 						builder.WriteByte('"')
-						for _, attrib := range attribs {
-							builder.WriteString(", .")
-							builder.WriteString(attrib)
-							builder.WriteString(" = true")
+						for _, init := range genAttribInits(attribs) {
+							builder.WriteString(", ")
+							builder.WriteString(init)
+						}
+						builder.WriteString(" };\n")
+						builder.WriteString("  inline static constexpr std::uint32_t binaryId = ")
+						builder.WriteString(strconv.FormatInt(binaryID, 10))
+						builder.WriteString(";\n")
+						builder.WriteString("  static decltype(")
+						builder.WriteString(typeExpr)
+						builder.WriteString("::")
+						builder.WriteString(fieldName.String())
+						builder.WriteString(") defaultValue() {\n    return ")
+						if defaultVal := c.genFieldDefault(c.types.Defs[fieldName].(*types.Var)); defaultVal != "" {
+							builder.WriteString(defaultVal)
+						} else {
+							builder.WriteString("{}")
 						}
-						builder.WriteString(" };\n};\n")
+						builder.WriteString(";\n  }\n};\n")
 						tagIndex++
 					}
 				}
@@ -387,20 +1114,357 @@ func (c *Compiler) genTypeMeta(typeSpec *ast.TypeSpec) string {
 			}
 		}
 		builder.WriteString("}")
-	case *ast.InterfaceType:
-		// Empty -- only used as generic constraint during typecheck
-	default:
-		// Empty -- alias declaration is definition
-	}
-
-	result := builder.String()
-	c.genTypeMetas[typeSpec] = result
-	return result
-}
 
-//
-// Functions
-//
+		// `gx::SchemaHash` specialization
+		if typeParams == "" {
+			hash := fnv.New64a()
+			hash.Write([]byte(c.schemaString(c.types.Defs[typeSpec.Name].Type(), map[*types.Named]bool{}, false)))
+			builder.WriteString("\ntemplate<>\nstruct gx::SchemaHash<")
+			builder.WriteString(typeExpr)
+			builder.WriteString("> {\n")
+			builder.WriteString("  inline static constexpr std::uint64_t value = ")
+			builder.WriteString(fmt.Sprintf("0x%016xull", hash.Sum64()))
+			builder.WriteString(";\n};")
+		}
+
+		// `gx::TypeInfo` specializations, one per instantiation for generic types
+		obj := c.types.Defs[typeSpec.Name]
+		var attribInits []string
+		if attribsDirective, ok := c.typeAttribs[obj]; ok {
+			attribs, err := parseFieldAttribs(attribsDirective)
+			if err != nil {
+				c.errorf(typeSpec.Pos(), "malformed attribs directive: %s", err)
+			}
+			attribs, _ = c.checkAttribs(c.typeAttribsType, "type", attribs, typeSpec.Pos())
+			attribInits = genAttribInits(attribs)
+		}
+		qualifier := func(pkg *types.Package) string {
+			if pkg == obj.Pkg() {
+				return ""
+			}
+			return pkg.Name()
+		}
+		instances := []types.Type{obj.Type()}
+		if typeParams != "" {
+			instances = c.typeInstances(obj)
+		}
+		for _, instance := range instances {
+			name := types.TypeString(instance, qualifier)
+			id := fnv.New64a()
+			id.Write([]byte(obj.Pkg().Path() + "." + name))
+			builder.WriteString("\ntemplate<>\nstruct gx::TypeInfo<")
+			builder.WriteString(trimFinalSpace(c.genTypeExpr(instance, typeSpec.Pos())))
+			builder.WriteString("> {\n")
+			builder.WriteString("  inline static constexpr const char *name = ")
+			builder.WriteString(cStringLiteral(name))
+			builder.WriteString(";\n  inline static constexpr const char *packagePath = ")
+			builder.WriteString(cStringLiteral(obj.Pkg().Path()))
+			builder.WriteString(";\n  inline static constexpr std::uint64_t id = ")
+			builder.WriteString(fmt.Sprintf("0x%016xull", id.Sum64()))
+			builder.WriteString(";\n  inline static constexpr gx::TypeAttribs attribs {")
+			if len(attribInits) > 0 {
+				builder.WriteString(" ")
+				builder.WriteString(strings.Join(attribInits, ", "))
+				builder.WriteString(" ")
+			}
+			builder.WriteString("};\n};")
+		}
+
+		// `gx::Layout` specialization
+		if c.layoutTypes[obj] {
+			name := typeSpec.Name.String()
+			nFields := 0
+			builder.WriteString("\ntemplate<>\nstruct gx::Layout<")
+			builder.WriteString(name)
+			builder.WriteString("> {\n")
+			for _, field := range typ.Fields.List {
+				fieldType := c.types.TypeOf(field.Type)
+				if fieldType != nil && len(field.Names) == 0 {
+					c.errorf(field.Pos(), "embedded fields not supported in layout types")
+				}
+				if fieldType == nil || len(field.Names) == 0 {
+					continue
+				}
+				var defaultVal string
+				if tag := field.Tag; tag != nil && tag.Kind == token.STRING {
+					unquoted, _ := strconv.Unquote(tag.Value)
+					defaultVal = reflect.StructTag(unquoted).Get("default")
+				}
+				descriptor := c.schemaString(fieldType, map[*types.Named]bool{}, true)
+				for _, fieldName := range field.Names {
+					if nFields == 0 {
+						builder.WriteString("  inline static constexpr gx::FieldLayout fields[] {\n")
+					}
+					nFields++
+					member := name + "::" + fieldName.String()
+					builder.WriteString("    { .name = \"")
+					builder.WriteString(fieldName.String())
+					builder.WriteString("\", .offset = offsetof(")
+					builder.WriteString(name)
+					builder.WriteString(", ")
+					builder.WriteString(fieldName.String())
+					builder.WriteString("), .size = sizeof(")
+					builder.WriteString(member)
+					builder.WriteString("), .type = ")
+					builder.WriteString(cStringLiteral(descriptor))
+					builder.WriteString(", .defaultValue = ")
+					builder.WriteString(cStringLiteral(defaultVal))
+					builder.WriteString(", .destroy = gx::destroyField<decltype(")
+					builder.WriteString(member)
+					builder.WriteString(")> },\n")
+				}
+			}
+			if nFields > 0 {
+				builder.WriteString("  };\n")
+			}
+			builder.WriteString("  inline static constexpr gx::StructLayout layout { .name = \"")
+			builder.WriteString(name)
+			builder.WriteString("\", .size = sizeof(")
+			builder.WriteString(name)
+			builder.WriteString("), .fields = ")
+			if nFields > 0 {
+				builder.WriteString("fields")
+			} else {
+				builder.WriteString("nullptr")
+			}
+			builder.WriteString(", .numFields = ")
+			builder.WriteString(strconv.Itoa(nFields))
+			builder.WriteString(" };\n};")
+		}
+	case *ast.InterfaceType:
+		// Empty -- only used as generic constraint during typecheck
+	default:
+		obj := c.types.Defs[typeSpec.Name]
+		if values, ok := c.enumValues[obj]; ok {
+			name := typeSpec.Name.String()
+			suffix := ""
+			if obj.Type().Underlying().(*types.Basic).Info()&types.IsUnsigned != 0 {
+				suffix = "ull"
+			}
+
+			// `forEachEnumValue`
+			builder.WriteString("inline void forEachEnumValue(")
+			builder.WriteString(name)
+			builder.WriteString(", auto &&func) {\n")
+			for _, value := range values {
+				builder.WriteString("  func(\"")
+				builder.WriteString(value.Name())
+				builder.WriteString("\", ")
+				builder.WriteString(name)
+				builder.WriteString("(")
+				builder.WriteString(value.Val().ExactString())
+				builder.WriteString(suffix)
+				builder.WriteString("));\n")
+			}
+			builder.WriteString("}\n")
+
+			// `nameOf`, using the first constant for values that have several
+			builder.WriteString("inline gx::String nameOf(")
+			builder.WriteString(name)
+			builder.WriteString(" val) {\n  switch (val) {\n")
+			seen := map[string]bool{}
+			for _, value := range values {
+				if str := value.Val().ExactString(); !seen[str] {
+					seen[str] = true
+					builder.WriteString("  case ")
+					builder.WriteString(str)
+					builder.WriteString(suffix)
+					builder.WriteString(":\n    return \"")
+					builder.WriteString(value.Name())
+					builder.WriteString("\";\n")
+				}
+			}
+			builder.WriteString("  }\n  return gx::unnamedEnumValue(\"")
+			builder.WriteString(name)
+			builder.WriteString("\", (long long)val);\n}")
+		}
+		// Otherwise empty -- alias declaration is definition
+	}
+
+	result := builder.String()
+	c.genTypeMetas[typeSpec] = result
+	return result
+}
+
+// From a `//gx:register MACRO when embeds PkgPath.TypeName` directive. Structs
+// embedding the type are exported and registered with `MACRO(Name);` before
+// their definition.
+type typeRegistration struct {
+	macro    string
+	embedded types.Object
+}
+
+// Finds `path` among `pkg` and its transitive imports
+func findImportedPackage(pkg *types.Package, path string) *types.Package {
+	visited := map[*types.Package]bool{}
+	var visit func(pkg *types.Package) *types.Package
+	visit = func(pkg *types.Package) *types.Package {
+		if visited[pkg] {
+			return nil
+		}
+		visited[pkg] = true
+		if pkg.Path() == path {
+			return pkg
+		}
+		for _, imported := range pkg.Imports() {
+			if found := visit(imported); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return visit(pkg)
+}
+
+type reflectedMethod struct {
+	decl    *ast.FuncDecl
+	attribs string
+	pos     token.Pos
+}
+
+// Booleans, numbers and strings, which tools can pass to and get from methods
+func isSimpleReflectType(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0
+}
+
+func (c *Compiler) collectReflectedMethod(decl *ast.FuncDecl, attribs string, pos token.Pos) {
+	if decl.Recv == nil {
+		c.errorf(pos, "reflect directive only applies to methods")
+		return
+	}
+	if !decl.Name.IsExported() {
+		c.errorf(pos, "reflected method %s must be exported", decl.Name.Name)
+		return
+	}
+	sig := c.types.Defs[decl.Name].Type().(*types.Signature)
+	recvType := sig.Recv().Type()
+	if ptr, ok := recvType.(*types.Pointer); ok {
+		recvType = ptr.Elem()
+	}
+	named, ok := recvType.(*types.Named)
+	if ok {
+		_, ok = named.Underlying().(*types.Struct)
+	}
+	if !ok {
+		c.errorf(pos, "reflected method %s must have a struct receiver", decl.Name.Name)
+		return
+	}
+	simple := sig.Results().Len() <= 1
+	for _, tuple := range []*types.Tuple{sig.Params(), sig.Results()} {
+		for i := 0; i < tuple.Len(); i++ {
+			simple = simple && isSimpleReflectType(tuple.At(i).Type())
+		}
+	}
+	if !simple {
+		c.errorf(pos, "reflected method %s may only take and return booleans, numbers or strings", decl.Name.Name)
+		return
+	}
+	obj := named.Origin().Obj()
+	c.reflectedMethods[obj] = append(c.reflectedMethods[obj], reflectedMethod{decl, attribs, pos})
+}
+
+// `gx::MethodTag` specializations and `forEachMethod` for `//gx:reflect`
+// methods. Separate from `genTypeMeta` since it calls the methods.
+func (c *Compiler) genMethodMeta(typeSpec *ast.TypeSpec) string {
+	if result, ok := c.genMethodMetas[typeSpec]; ok {
+		return result
+	}
+
+	builder := &strings.Builder{}
+	if methods := c.reflectedMethods[c.types.Defs[typeSpec.Name]]; len(methods) > 0 {
+		typeParams, typeExpr := genMetaTypeParamsAndExpr(typeSpec)
+
+		// `gx::MethodTag` specializations
+		for tagIndex, method := range methods {
+			uppercase := false
+			var attribs, parsed []FieldAttrib
+			if method.attribs != "" {
+				var err error
+				if parsed, err = parseFieldAttribs(method.attribs); err != nil {
+					c.errorf(method.pos, "malformed reflect directive: %s", err)
+				}
+			}
+			for _, attrib := range parsed {
+				switch {
+				case attrib.key == "uppercase" && attrib.kind == "flag":
+					uppercase = true
+				case attrib.key == "name":
+					c.errorf(method.pos, "method attrib name is set from the method's name")
+				default:
+					attribs = append(attribs, attrib)
+				}
+			}
+			attribs, _ = c.checkAttribs(c.methodAttribsType, "method", attribs, method.pos)
+			builder.WriteString("template<")
+			builder.WriteString(typeParams)
+			builder.WriteString(">\nstruct gx::MethodTag<")
+			builder.WriteString(typeExpr)
+			builder.WriteString(", ")
+			builder.WriteString(strconv.Itoa(tagIndex))
+			builder.WriteString("> {\n")
+			builder.WriteString("  inline static constexpr gx::MethodAttribs attribs { .name = \"")
+			if uppercase {
+				builder.WriteString(method.decl.Name.String())
+			} else {
+				builder.WriteString(lowerFirst(method.decl.Name.String()))
+			}
+			builder.WriteByte('"')
+			for _, init := range genAttribInits(attribs) {
+				builder.WriteString(", ")
+				builder.WriteString(init)
+			}
+			builder.WriteString(" };\n};\n")
+		}
+
+		// `forEachMethod`, passing each tag with a callable that invokes the method
+		if typeParams != "" {
+			builder.WriteString("template<")
+			builder.WriteString(typeParams)
+			builder.WriteString(">\n")
+		}
+		builder.WriteString("inline void forEachMethod(")
+		builder.WriteString(typeExpr)
+		builder.WriteString(" &val, auto &&func) {\n")
+		for tagIndex, method := range methods {
+			sig := c.types.Defs[method.decl.Name].Type().(*types.Signature)
+			builder.WriteString("  func(gx::MethodTag<")
+			builder.WriteString(typeExpr)
+			builder.WriteString(", ")
+			builder.WriteString(strconv.Itoa(tagIndex))
+			builder.WriteString(">(), [&](")
+			for i := 0; i < sig.Params().Len(); i++ {
+				if i > 0 {
+					builder.WriteString(", ")
+				}
+				builder.WriteString(c.genTypeExpr(sig.Params().At(i).Type(), method.pos))
+				builder.WriteString("arg")
+				builder.WriteString(strconv.Itoa(i))
+			}
+			builder.WriteString(") {\n    return ")
+			builder.WriteString(method.decl.Name.String())
+			builder.WriteString("(")
+			if _, ok := sig.Recv().Type().(*types.Pointer); ok {
+				builder.WriteString("&")
+			}
+			builder.WriteString("val")
+			for i := 0; i < sig.Params().Len(); i++ {
+				builder.WriteString(", arg")
+				builder.WriteString(strconv.Itoa(i))
+			}
+			builder.WriteString(");\n  });\n")
+		}
+		builder.WriteString("}")
+	}
+
+	result := builder.String()
+	c.genMethodMetas[typeSpec] = result
+	return result
+}
+
+//
+// Functions
+//
 
 var methodFieldTagRe = regexp.MustCompile(`^(.*)_([^_]*)$`)
 
@@ -415,6 +1479,45 @@ func (c *Compiler) genFuncDecl(decl *ast.FuncDecl) string {
 
 	builder := &strings.Builder{}
 
+	// WGSL declares types after names, so handle it separately
+	if c.target == WGSL {
+		builder.WriteString("fn ")
+		builder.WriteString(decl.Name.String())
+		builder.WriteByte('(')
+		var params []*types.Var
+		if recv != nil {
+			params = append(params, recv)
+		}
+		for i, nParams := 0, sig.Params().Len(); i < nParams; i++ {
+			params = append(params, sig.Params().At(i))
+		}
+		for i, param := range params {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			builder.WriteString(param.Name())
+			builder.WriteString(": ")
+			builder.WriteString(trimFinalSpace(c.genTypeExpr(param.Type(), param.Pos())))
+			if c.wgslIsTexture(param.Type()) {
+				builder.WriteString(", ")
+				builder.WriteString(param.Name())
+				builder.WriteString("_sampler: sampler")
+			}
+		}
+		builder.WriteByte(')')
+		if rets := sig.Results(); rets.Len() > 1 {
+			c.errorf(decl.Type.Results.Pos(), "multiple return values not supported")
+		} else if rets.Len() == 1 {
+			ret := rets.At(0)
+			builder.WriteString(" -> ")
+			builder.WriteString(trimFinalSpace(c.genTypeExpr(ret.Type(), ret.Pos())))
+		}
+
+		result := builder.String()
+		c.genFuncDecls[c.target][decl] = result
+		return result
+	}
+
 	// Type parameters
 	addTypeParams := func(typeParams *types.TypeParamList) {
 		if typeParams != nil {
@@ -497,6 +1600,9 @@ func (c *Compiler) genFuncDecl(decl *ast.FuncDecl) string {
 	}
 
 	// Name
+	if initName, ok := c.initFuncNames[decl]; ok {
+		name = initName
+	}
 	builder.WriteString(name)
 
 	// Parameters
@@ -507,6 +1613,9 @@ func (c *Compiler) genFuncDecl(decl *ast.FuncDecl) string {
 			builder.WriteString("auto &&")
 		} else if basicType, ok := typ.(*types.Basic); ok && basicType.Kind() == types.String {
 			builder.WriteString("const gx::String &")
+		} else if c.storageBuffers[param] {
+			builder.WriteString(trimFinalSpace(c.genTypeExpr(typ, param.Pos())))
+			builder.WriteString(" &") // Shared with the caller, like on the GPU
 		} else {
 			builder.WriteString(c.genTypeExpr(typ, param.Pos()))
 		}
@@ -546,6 +1655,15 @@ func (c *Compiler) writeIdent(ident *ast.Ident) {
 		c.write("gx::")
 	}
 	if ext, ok := c.externs[c.target][c.types.Uses[ident]]; ok {
+		switch c.target {
+		case GLSL:
+			if c.glslProfile == "100" && glslBuiltinsSince300[ext] {
+				c.errorf(ident.Pos(), "%s needs GLSL profile 300es or later", ext)
+			}
+			ext = c.glslBuiltin(ext)
+		case WGSL:
+			ext = c.wgslHelper(ext, ident)
+		}
 		c.write(ext)
 	} else {
 		c.write(ident.Name) // TODO: Package namespace
@@ -556,8 +1674,7 @@ func (c *Compiler) writeBasicLit(lit *ast.BasicLit) {
 	switch lit.Kind {
 	case token.INT:
 		c.write(lit.Value)
-		switch c.target {
-		case GLSL:
+		if c.target != CPP && !c.shaderInts() {
 			c.write(".0")
 		}
 	case token.FLOAT:
@@ -581,6 +1698,253 @@ func (c *Compiler) writeBasicLit(lit *ast.BasicLit) {
 	}
 }
 
+func cStringLiteral(s string) string {
+	builder := &strings.Builder{}
+	builder.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; ch {
+		case '"', '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(ch)
+		case '\n':
+			builder.WriteString("\\n")
+		case '\t':
+			builder.WriteString("\\t")
+		case '\r':
+			builder.WriteString("\\r")
+		default:
+			if ch < ' ' || ch == 0x7f {
+				fmt.Fprintf(builder, "\\%03o", ch)
+			} else {
+				builder.WriteByte(ch)
+			}
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+func (c *Compiler) dependsOnExterns(expr ast.Expr) bool {
+	if expr == nil {
+		return false
+	}
+	result := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			obj := c.types.Uses[ident]
+			if _, ok := c.externs[c.target][obj]; ok {
+				result = true
+			} else if valueExpr, ok := c.constValueExprs[obj]; ok && c.dependsOnExterns(valueExpr) {
+				result = true
+			}
+		}
+		return !result
+	})
+	return result
+}
+
+func (c *Compiler) dependsOnOverrides(expr ast.Expr) bool {
+	if expr == nil || len(c.constOverrides) == 0 {
+		return false
+	}
+	result := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			obj := c.types.Uses[ident]
+			if _, ok := c.constOverrides[obj]; ok {
+				result = true
+			} else if valueExpr, ok := c.constValueExprs[obj]; ok && c.dependsOnOverrides(valueExpr) {
+				result = true
+			}
+		}
+		return !result
+	})
+	return result
+}
+
+// Value of a constant expression, re-evaluated if it depends on constants
+// overridden by the shader variant being output
+func (c *Compiler) constantValue(expr ast.Expr) constant.Value {
+	typeAndValue := c.types.Types[expr]
+	if typeAndValue.Value == nil || !c.dependsOnOverrides(expr) {
+		return typeAndValue.Value
+	}
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return c.constObjValue(c.types.Uses[expr])
+	case *ast.SelectorExpr:
+		return c.constObjValue(c.types.Uses[expr.Sel])
+	case *ast.ParenExpr:
+		return c.constantValue(expr.X)
+	case *ast.UnaryExpr:
+		return constant.UnaryOp(expr.Op, c.constantValue(expr.X), 0)
+	case *ast.BinaryExpr:
+		x, y := c.constantValue(expr.X), c.constantValue(expr.Y)
+		switch expr.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return constant.MakeBool(constant.Compare(x, expr.Op, y))
+		case token.SHL, token.SHR:
+			shift, _ := constant.Uint64Val(constant.ToInt(y))
+			return constant.Shift(x, expr.Op, uint(shift))
+		case token.QUO:
+			if basic, ok := typeAndValue.Type.Underlying().(*types.Basic); ok && basic.Info()&types.IsInteger != 0 {
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y) // Integer division
+			}
+		}
+		return constant.BinaryOp(x, expr.Op, y)
+	case *ast.CallExpr:
+		if len(expr.Args) == 1 && c.types.Types[expr.Fun].IsType() { // Conversion
+			val := c.constantValue(expr.Args[0])
+			if basic, ok := typeAndValue.Type.Underlying().(*types.Basic); ok {
+				switch {
+				case basic.Info()&types.IsInteger != 0:
+					return constant.ToInt(val)
+				case basic.Info()&types.IsFloat != 0:
+					return constant.ToFloat(val)
+				}
+			}
+			return val
+		}
+	}
+	c.errorf(expr.Pos(), "unsupported constant expression for shader variant")
+	return typeAndValue.Value
+}
+
+func (c *Compiler) constObjValue(obj types.Object) constant.Value {
+	if val, ok := c.constOverrides[obj]; ok {
+		return val
+	}
+	if valueExpr, ok := c.constValueExprs[obj]; ok && c.dependsOnOverrides(valueExpr) {
+		return c.constantValue(valueExpr)
+	}
+	if constObj, ok := obj.(*types.Const); ok {
+		return constObj.Val()
+	}
+	return nil
+}
+
+func constantBasicType(val constant.Value, typ types.Type) *types.Basic {
+	if basic, ok := typ.Underlying().(*types.Basic); ok {
+		return basic
+	}
+	switch val.Kind() { // Type parameters: use the default type of the value
+	case constant.Bool:
+		return types.Typ[types.UntypedBool]
+	case constant.String:
+		return types.Typ[types.UntypedString]
+	case constant.Int:
+		return types.Typ[types.UntypedInt]
+	case constant.Float:
+		return types.Typ[types.UntypedFloat]
+	}
+	return types.Typ[types.Invalid]
+}
+
+func (c *Compiler) constantFits(val constant.Value, typ types.Type) bool {
+	basic := constantBasicType(val, typ)
+	switch {
+	case basic.Info()&types.IsInteger != 0:
+		bits, signed := 32, true
+		switch basic.Kind() {
+		case types.Int8:
+			bits = 8
+		case types.Int16:
+			bits = 16
+		case types.Int64:
+			bits = 64
+		case types.Uint, types.Uint32:
+			signed = false
+		case types.Uint8:
+			bits, signed = 8, false
+		case types.Uint16:
+			bits, signed = 16, false
+		case types.Uint64, types.Uintptr:
+			bits, signed = 64, false
+		}
+		min, max := constant.MakeInt64(0), constant.Shift(constant.MakeInt64(1), token.SHL, uint(bits))
+		if signed {
+			max = constant.Shift(max, token.SHR, 1)
+			min = constant.UnaryOp(token.SUB, max, 0)
+		}
+		val = constant.ToInt(val)
+		return val.Kind() == constant.Int && constant.Compare(val, token.GEQ, min) && constant.Compare(val, token.LSS, max)
+	case basic.Info()&types.IsFloat != 0:
+		f, _ := constant.Float32Val(constant.ToFloat(val))
+		return !math.IsInf(float64(f), 0) // All floats are single precision in output
+	}
+	return true
+}
+
+func (c *Compiler) writeConstant(val constant.Value, typ types.Type, pos token.Pos) {
+	if !c.constantFits(val, typ) {
+		c.errorf(pos, "constant %s overflows %s", val.String(), typ.String())
+		return
+	}
+	if c.target == CPP && c.isEnumType(typ) {
+		c.write(typ.(*types.Named).Obj().Name())
+		c.write("(")
+		defer c.write(")")
+	}
+	basic := constantBasicType(val, typ)
+	switch {
+	case basic.Info()&types.IsBoolean != 0:
+		c.write(strconv.FormatBool(constant.BoolVal(val)))
+	case basic.Info()&types.IsString != 0:
+		c.write(cStringLiteral(constant.StringVal(val)))
+	case basic.Info()&types.IsInteger != 0:
+		val = constant.ToInt(val)
+		switch c.target {
+		case CPP:
+			suffix := ""
+			switch basic.Kind() {
+			case types.Uint, types.Uint32:
+				suffix = "u"
+			case types.Int64:
+				suffix = "ll"
+			case types.Uint64, types.Uintptr:
+				suffix = "ull"
+			}
+			str := val.ExactString()
+			if constant.Sign(val) < 0 && !c.constantFits(constant.UnaryOp(token.SUB, val, 0), typ) {
+				// Minimum signed value can't be written as a negated literal
+				str = "(" + constant.BinaryOp(val, token.ADD, constant.MakeInt64(1)).ExactString() + suffix + " - 1)"
+				suffix = ""
+			}
+			switch basic.Kind() {
+			case types.Int8, types.Int16, types.Uint8, types.Uint16:
+				c.write(trimFinalSpace(c.genTypeExpr(basic, pos)))
+				c.write("(")
+				c.write(str)
+				c.write(")")
+			default:
+				c.write(str)
+				c.write(suffix)
+			}
+		case GLSL, WGSL:
+			c.write(val.ExactString())
+			switch {
+			case !c.shaderInts():
+				c.write(".0")
+			case basic.Info()&types.IsUnsigned != 0:
+				c.write("u")
+			}
+		}
+	case basic.Info()&types.IsFloat != 0:
+		f, _ := constant.Float32Val(constant.ToFloat(val))
+		str := strconv.FormatFloat(float64(f), 'g', -1, 32)
+		if !strings.ContainsAny(str, ".e") {
+			str += ".0"
+		}
+		c.write(str)
+		switch c.target {
+		case CPP:
+			c.write("f")
+		}
+	default:
+		c.errorf(pos, "unsupported constant type %s", typ.String())
+	}
+}
+
 func (c *Compiler) writeFuncLit(lit *ast.FuncLit) {
 	sig := c.types.TypeOf(lit).(*types.Signature)
 	if c.indent == 0 {
@@ -607,70 +1971,176 @@ func (c *Compiler) writeFuncLit(lit *ast.FuncLit) {
 	c.atBlockEnd = false
 }
 
+func (c *Compiler) hasSideEffects(expr ast.Expr) bool {
+	result := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if !c.types.Types[node.Fun].IsType() {
+				result = true
+			}
+		}
+		return !result
+	})
+	return result
+}
+
 func (c *Compiler) writeCompositeLit(lit *ast.CompositeLit) {
-	useParens := c.target == GLSL
-	typeExpr := (c.genTypeExpr(c.types.TypeOf(lit), lit.Pos()))
-	if useParens {
-		c.write(trimFinalSpace(typeExpr))
-		c.write("(")
-	} else {
-		c.write(typeExpr)
-		c.write("{")
-	}
-	if len(lit.Elts) > 0 {
-		if _, ok := lit.Elts[0].(*ast.KeyValueExpr); ok {
+	// Reorder keyed struct elements into definition order, hoisting values into
+	// temporaries if that would change the order of side effects
+	elts := lit.Elts
+	temps := map[ast.Expr]string{}
+	if len(elts) > 0 {
+		if _, ok := elts[0].(*ast.KeyValueExpr); ok {
 			if typ, ok := c.types.TypeOf(lit).Underlying().(*types.Struct); ok {
-				if nFields := typ.NumFields(); nFields != 0 {
+				nFields := typ.NumFields()
+				if nFields != 0 {
 					if _, ok := c.fieldIndices[typ.Field(0)]; !ok {
 						for i := 0; i < nFields; i++ {
 							c.fieldIndices[typ.Field(i)] = i
 						}
 					}
 				}
-				lastIndex := 0
-				for _, elt := range lit.Elts {
-					field := c.types.ObjectOf(elt.(*ast.KeyValueExpr).Key.(*ast.Ident)).(*types.Var)
-					if index := c.fieldIndices[field]; index < lastIndex {
-						c.errorf(lit.Pos(), "struct literal fields must appear in definition order")
-						break
-					} else {
-						lastIndex = index
+				fieldIndex := func(elt ast.Expr) int {
+					return c.fieldIndices[c.types.ObjectOf(elt.(*ast.KeyValueExpr).Key.(*ast.Ident)).(*types.Var)]
+				}
+				elts = append([]ast.Expr{}, lit.Elts...)
+				sort.SliceStable(elts, func(i, j int) bool {
+					return fieldIndex(elts[i]) < fieldIndex(elts[j])
+				})
+				reordered, sideEffects := false, false
+				for i, elt := range lit.Elts {
+					if elts[i] != elt {
+						reordered = true
+					}
+					if c.hasSideEffects(elt.(*ast.KeyValueExpr).Value) {
+						sideEffects = true
+					}
+				}
+				if reordered && sideEffects {
+					for _, elt := range lit.Elts {
+						if value := elt.(*ast.KeyValueExpr).Value; c.types.Types[value].Value == nil {
+							temps[value] = c.generateIdentifier("Field")
+						}
 					}
 				}
 			}
 		}
-		if c.fileSet.Position(lit.Pos()).Line == c.fileSet.Position(lit.Elts[0].Pos()).Line {
-			if !useParens {
-				c.write(" ")
+	}
+	if c.target != CPP {
+		// Shader struct constructors take every field, so fill omitted ones in
+		// from their defaults
+		if typ, ok := c.types.TypeOf(lit).Underlying().(*types.Struct); ok && len(elts) != typ.NumFields() {
+			values := map[*types.Var]ast.Expr{}
+			for _, elt := range elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					values[c.types.ObjectOf(kv.Key.(*ast.Ident)).(*types.Var)] = kv.Value
+				}
 			}
-			for i, elt := range lit.Elts {
-				if i > 0 {
-					c.write(", ")
+			elts = nil
+			for i, nFields := 0, typ.NumFields(); i < nFields; i++ {
+				field := typ.Field(i)
+				if value, ok := values[field]; ok {
+					elts = append(elts, value)
+				} else if value, ok := c.fieldDefaults[field.Origin()]; ok {
+					elts = append(elts, value)
+				} else {
+					c.errorf(lit.Pos(), "GXSL struct literals must set every field that has no default")
+					break
 				}
-				c.writeExpr(elt)
 			}
-			if !useParens {
-				c.write(" ")
+		}
+	}
+	if len(temps) > 0 {
+		switch c.target {
+		case CPP:
+			if c.indent == 0 {
+				c.write("[]() {\n")
+			} else {
+				c.write("[&]() {\n")
 			}
-		} else {
-			c.write("\n")
 			c.indent++
-			nElts := len(lit.Elts)
-			for i, elt := range lit.Elts {
-				c.writeExpr(elt)
-				if !(useParens && i == nElts-1) {
-					c.write(",")
+			for _, elt := range lit.Elts {
+				if value := elt.(*ast.KeyValueExpr).Value; temps[value] != "" {
+					c.write("auto ")
+					c.write(temps[value])
+					c.write(" = ")
+					c.writeExpr(value)
+					c.write(";\n")
 				}
-				c.write("\n")
 			}
-			c.indent--
+			c.write("return ")
+		case GLSL, WGSL:
+			c.errorf(lit.Pos(), "GXSL struct literals with side effects must list fields in definition order")
 		}
 	}
+
+	useParens := c.target != CPP
+	typeExpr := (c.genTypeExpr(c.types.TypeOf(lit), lit.Pos()))
 	if useParens {
-		c.write(")")
+		c.write(trimFinalSpace(typeExpr))
+		c.write("(")
+	} else {
+		c.write(typeExpr)
+		c.write("{")
+	}
+	writeElt := func(elt ast.Expr) {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if useParens {
+				c.writeExpr(kv.Value)
+				return
+			} else if temp, ok := temps[kv.Value]; ok {
+				c.write(".")
+				c.writeIdent(kv.Key.(*ast.Ident))
+				c.write(" = std::move(")
+				c.write(temp)
+				c.write(")")
+				return
+			}
+		}
+		c.writeExpr(elt)
+	}
+	if len(elts) > 0 {
+		if len(lit.Elts) == 0 || c.fileSet.Position(lit.Pos()).Line == c.fileSet.Position(lit.Elts[0].Pos()).Line {
+			if !useParens {
+				c.write(" ")
+			}
+			for i, elt := range elts {
+				if i > 0 {
+					c.write(", ")
+				}
+				writeElt(elt)
+			}
+			if !useParens {
+				c.write(" ")
+			}
+		} else {
+			c.write("\n")
+			c.indent++
+			nElts := len(elts)
+			for i, elt := range elts {
+				writeElt(elt)
+				if !(useParens && i == nElts-1) {
+					c.write(",")
+				}
+				c.write("\n")
+			}
+			c.indent--
+		}
+	}
+	if useParens {
+		c.write(")")
 	} else {
 		c.write("}")
 	}
+
+	if len(temps) > 0 && c.target == CPP {
+		c.write(";\n")
+		c.indent--
+		c.write("}()")
+	}
 }
 
 func (c *Compiler) writeParenExpr(bin *ast.ParenExpr) {
@@ -681,13 +2151,16 @@ func (c *Compiler) writeParenExpr(bin *ast.ParenExpr) {
 
 func (c *Compiler) writeSelectorExpr(sel *ast.SelectorExpr) {
 	switch c.target {
-	case GLSL:
+	case GLSL, WGSL:
 		if ident, ok := sel.X.(*ast.Ident); ok {
-			if glslStorageClass(ident.Name) != "" {
-				if ext, ok := c.externs[GLSL][c.types.Uses[sel.Sel]]; ok {
+			if storageClass := glslStorageClass(ident.Name); storageClass != "" {
+				if ext, ok := c.externs[c.target][c.types.Uses[sel.Sel]]; ok {
 					c.write(ext)
+				} else if c.target == WGSL && storageClass == "uniform" && !c.wgslIsTexture(c.types.TypeOf(sel)) {
+					c.write("gx_uniforms.")
+					c.write(sel.Sel.Name)
 				} else {
-					c.write(c.types.TypeOf(sel.X).(*types.Named).Obj().Name())
+					c.write(glslStorageType(c.types.TypeOf(sel.X)).Obj().Name())
 					c.write("_")
 					c.write(sel.Sel.Name)
 				}
@@ -722,6 +2195,22 @@ func (c *Compiler) writeIndexExpr(ind *ast.IndexExpr) {
 }
 
 func (c *Compiler) writeCallExpr(call *ast.CallExpr) {
+	// Length of a storage buffer
+	if ident, ok := call.Fun.(*ast.Ident); ok && c.target != CPP && len(call.Args) == 1 {
+		if builtin, ok := c.types.Uses[ident].(*types.Builtin); ok && builtin.Name() == "len" {
+			switch c.target {
+			case GLSL:
+				c.writeExpr(call.Args[0])
+				c.write(".length()")
+			case WGSL:
+				c.write("i32(arrayLength(&")
+				c.writeExpr(call.Args[0])
+				c.write("))")
+			}
+			return
+		}
+	}
+
 	method := false
 	funType := c.types.Types[call.Fun]
 	if _, ok := funType.Type.Underlying().(*types.Signature); ok || funType.IsBuiltin() {
@@ -730,17 +2219,23 @@ func (c *Compiler) writeCallExpr(call *ast.CallExpr) {
 			obj := c.types.Uses[sel.Sel]
 			if sig, ok := obj.Type().(*types.Signature); ok && sig.Recv() != nil {
 				switch c.target {
-				case GLSL:
-					if ext, ok := c.externs[GLSL][c.types.Uses[sel.Sel]]; ok && !unicode.IsLetter(rune(ext[0])) {
-						switch len(call.Args) {
-						case 0:
+				case GLSL, WGSL:
+					if ext, ok := c.externs[c.target][c.types.Uses[sel.Sel]]; ok && !unicode.IsLetter(rune(ext[0])) {
+						switch {
+						case ext[0] == '.' && len(call.Args) == 0: // Swizzle
+							c.write("(")
+							c.writeExpr(sel.X)
+							c.write(")")
+							c.write(ext)
+							return
+						case len(call.Args) == 0:
 							c.write("(")
 							c.write(ext)
 							c.write("(")
 							c.writeExpr(sel.X)
 							c.write("))")
 							return
-						case 1:
+						case len(call.Args) == 1:
 							c.write("((")
 							c.writeExpr(sel.X)
 							c.write(") ")
@@ -832,6 +2327,11 @@ func (c *Compiler) writeCallExpr(call *ast.CallExpr) {
 			c.write(", ")
 		}
 		c.writeExpr(arg)
+		if c.wgslIsTexture(c.types.TypeOf(arg)) {
+			c.write(", ")
+			c.writeExpr(arg)
+			c.write("_sampler")
+		}
 	}
 	c.write(")")
 }
@@ -843,6 +2343,12 @@ func (c *Compiler) writeStarExpr(star *ast.StarExpr) {
 }
 
 func (c *Compiler) writeUnaryExpr(un *ast.UnaryExpr) {
+	if c.target == CPP && c.isEnumType(c.types.TypeOf(un)) {
+		// Keep the enum type, C++ arithmetic gives the underlying integer
+		c.write(c.types.TypeOf(un).(*types.Named).Obj().Name())
+		c.write("(")
+		defer c.write(")")
+	}
 	switch op := un.Op; op {
 	case token.ADD, token.SUB, token.NOT:
 		c.write(op.String())
@@ -858,6 +2364,12 @@ func (c *Compiler) writeUnaryExpr(un *ast.UnaryExpr) {
 }
 
 func (c *Compiler) writeBinaryExpr(bin *ast.BinaryExpr) {
+	if c.target == CPP && c.isEnumType(c.types.TypeOf(bin)) {
+		// Keep the enum type, C++ arithmetic gives the underlying integer
+		c.write(c.types.TypeOf(bin).(*types.Named).Obj().Name())
+		c.write("(")
+		defer c.write(")")
+	}
 	needParens := false
 	switch bin.Op {
 	case token.AND, token.OR, token.XOR:
@@ -866,6 +2378,16 @@ func (c *Compiler) writeBinaryExpr(bin *ast.BinaryExpr) {
 	if needParens {
 		c.write("(")
 	}
+	switch bin.Op {
+	case token.EQL, token.NEQ:
+		operand := bin.X
+		if c.types.Types[operand].IsNil() {
+			operand = bin.Y
+		}
+		if typ := c.types.TypeOf(operand); !types.Comparable(typ) {
+			c.errorf(bin.OpPos, "comparison of %s not supported", typ.String())
+		}
+	}
 	c.writeExpr(bin.X)
 	c.write(" ")
 	switch op := bin.Op; op {
@@ -896,6 +2418,10 @@ func (c *Compiler) writeKeyValueExpr(kv *ast.KeyValueExpr) {
 }
 
 func (c *Compiler) writeExpr(expr ast.Expr) {
+	if typeAndValue := c.types.Types[expr]; typeAndValue.Value != nil && !c.dependsOnExterns(expr) {
+		c.writeConstant(c.constantValue(expr), typeAndValue.Type, expr.Pos())
+		return
+	}
 	switch expr := expr.(type) {
 	case *ast.Ident:
 		c.writeIdent(expr)
@@ -957,6 +2483,8 @@ func (c *Compiler) writeAssignStmt(assignStmt *ast.AssignStmt) {
 			}
 		case GLSL:
 			c.write(c.genTypeExpr(typ, assignStmt.Pos()))
+		case WGSL:
+			c.write("var ")
 		}
 	}
 	c.writeExpr(assignStmt.Lhs[0])
@@ -1015,7 +2543,37 @@ func (c *Compiler) writeBlockStmt(block *ast.BlockStmt) {
 	c.atBlockEnd = true
 }
 
+// Shaders only get the branch taken by an `if` with a constant condition, so
+// that variants don't carry code for the others
+func (c *Compiler) constantIfBranch(ifStmt *ast.IfStmt) (ast.Stmt, bool) {
+	if c.target == CPP || ifStmt.Init != nil || c.dependsOnExterns(ifStmt.Cond) {
+		return nil, false
+	}
+	val := c.constantValue(ifStmt.Cond)
+	if val == nil || val.Kind() != constant.Bool {
+		return nil, false
+	}
+	if constant.BoolVal(val) {
+		return ifStmt.Body, true
+	}
+	return ifStmt.Else, true
+}
+
+func (c *Compiler) isDeadStmt(stmt ast.Stmt) bool {
+	if ifStmt, ok := stmt.(*ast.IfStmt); ok {
+		branch, ok := c.constantIfBranch(ifStmt)
+		return ok && (branch == nil || c.isDeadStmt(branch))
+	}
+	return false
+}
+
 func (c *Compiler) writeIfStmt(ifStmt *ast.IfStmt) {
+	if branch, ok := c.constantIfBranch(ifStmt); ok {
+		if branch != nil {
+			c.writeStmt(branch)
+		}
+		return
+	}
 	c.write("if (")
 	if ifStmt.Init != nil {
 		c.writeStmt(ifStmt.Init)
@@ -1024,7 +2582,7 @@ func (c *Compiler) writeIfStmt(ifStmt *ast.IfStmt) {
 	c.writeExpr(ifStmt.Cond)
 	c.write(") ")
 	c.writeStmt(ifStmt.Body)
-	if ifStmt.Else != nil {
+	if ifStmt.Else != nil && !c.isDeadStmt(ifStmt.Else) {
 		c.write(" else ")
 		c.writeStmt(ifStmt.Else)
 	}
@@ -1105,6 +2663,40 @@ func (c *Compiler) writeDeclStmt(declStmt *ast.DeclStmt) {
 					}
 				}
 				c.write(typeDefnIndented.String())
+			case *ast.ValueSpec:
+				if decl.Tok != token.VAR {
+					c.errorf(declStmt.Pos(), "unsupported declaration statement")
+					continue
+				}
+				if len(spec.Values) > 0 && len(spec.Values) != len(spec.Names) {
+					c.errorf(spec.Pos(), "multi-value declaration unsupported")
+					continue
+				}
+				for i, name := range spec.Names {
+					if i > 0 {
+						c.write(";\n")
+					}
+					typ := c.types.TypeOf(name)
+					switch c.target {
+					case CPP, GLSL:
+						c.write(c.genTypeExpr(typ, name.Pos()))
+						c.writeIdent(name)
+					case WGSL:
+						c.write("var ")
+						c.writeIdent(name)
+						c.write(": ")
+						c.write(trimFinalSpace(c.genTypeExpr(typ, name.Pos())))
+					}
+					if len(spec.Values) > 0 {
+						c.write(" = ")
+						c.writeExpr(spec.Values[i])
+					} else if c.target == CPP {
+						c.write(" {}") // Value-initialized, so struct fields get their defaults
+					} else {
+						c.write(" = ")
+						c.writeZeroValue(typ, name.Pos())
+					}
+				}
 			default:
 				c.errorf(declStmt.Pos(), "unsupported declaration statement")
 			}
@@ -1112,6 +2704,62 @@ func (c *Compiler) writeDeclStmt(declStmt *ast.DeclStmt) {
 	}
 }
 
+// Writes the zero value of a type for shaders, where locals have to be
+// initialized explicitly. Struct fields with `default` tags get their defaults.
+func (c *Compiler) writeZeroValue(typ types.Type, pos token.Pos) {
+	typeExpr := trimFinalSpace(c.genTypeExpr(typ, pos))
+	if named, ok := typ.(*types.Named); ok && c.externs[c.target][named.Origin().Obj()] != "" {
+		// Built-in vectors and matrices
+		if c.target == WGSL {
+			c.write(typeExpr)
+			c.write("()")
+		} else {
+			c.write(typeExpr)
+			c.write("(0.0)")
+		}
+		return
+	}
+	switch under := typ.Underlying().(type) {
+	case *types.Basic:
+		if under.Info()&types.IsBoolean != 0 {
+			c.writeConstant(constant.MakeBool(false), typ, pos)
+		} else {
+			c.writeConstant(constant.MakeInt64(0), typ, pos)
+		}
+	case *types.Struct:
+		c.write(typeExpr)
+		c.write("(")
+		for i, nFields := 0, under.NumFields(); i < nFields; i++ {
+			if i > 0 {
+				c.write(", ")
+			}
+			field := under.Field(i)
+			if value, ok := c.fieldDefaults[field.Origin()]; ok {
+				c.writeExpr(value)
+			} else {
+				c.writeZeroValue(field.Type(), pos)
+			}
+		}
+		c.write(")")
+	case *types.Array:
+		if c.target == WGSL {
+			c.write(typeExpr)
+			c.write("(")
+			for i := int64(0); i < under.Len(); i++ {
+				if i > 0 {
+					c.write(", ")
+				}
+				c.writeZeroValue(under.Elem(), pos)
+			}
+			c.write(")")
+		} else {
+			c.errorf(pos, "zero values of arrays aren't supported in GLSL")
+		}
+	default:
+		c.errorf(pos, "%s has no zero value in GXSL", typ)
+	}
+}
+
 func (c *Compiler) writeStmt(stmt ast.Stmt) {
 	switch stmt := stmt.(type) {
 	case *ast.ExprStmt:
@@ -1143,6 +2791,9 @@ func (c *Compiler) writeStmt(stmt ast.Stmt) {
 
 func (c *Compiler) writeStmtList(list []ast.Stmt) {
 	for _, stmt := range list {
+		if c.isDeadStmt(stmt) {
+			continue
+		}
 		c.writeStmt(stmt)
 		if !c.atBlockEnd {
 			c.write(";")
@@ -1157,39 +2808,470 @@ func (c *Compiler) writeStmtList(list []ast.Stmt) {
 
 func glslStorageClass(name string) string {
 	switch name {
-	case "attributes", "uniforms", "varyings":
+	case "attributes", "uniforms", "varyings", "outputs":
 		return name[0 : len(name)-1]
 	}
 	return ""
 }
 
+func (c *Compiler) glslQualifier(storageClass, stage string) string {
+	if c.glslProfile == "100" {
+		return storageClass
+	}
+	switch storageClass {
+	case "attribute":
+		return "in"
+	case "varying":
+		if stage == "vertex" {
+			return "out"
+		}
+		return "in"
+	case "output":
+		return "out"
+	}
+	return storageClass
+}
+
+func (c *Compiler) shaderInts() bool {
+	return c.target == WGSL || (c.target == GLSL && c.glslProfile != "100")
+}
+
+func (c *Compiler) setGLSLProfile(profile string) {
+	if profile != c.glslProfile {
+		// Generated GLSL depends on the profile, so don't reuse it across profiles
+		c.glslProfile = profile
+		c.genTypeExprs[GLSL] = map[types.Type]string{}
+		c.genTypeDefns[GLSL] = map[*ast.TypeSpec]string{}
+		c.genFuncDecls[GLSL] = map[*ast.FuncDecl]string{}
+	}
+}
+
+// Built-in functions that GLSL ES 1.00 lacks
+var glslBuiltinsSince300 = map[string]bool{
+	"sinh": true, "cosh": true, "tanh": true, "asinh": true, "acosh": true, "atanh": true,
+	"trunc": true, "round": true, "roundEven": true,
+	"transpose": true, "determinant": true, "inverse": true,
+	"dFdx": true, "dFdy": true, "fwidth": true,
+}
+
+func (c *Compiler) glslBuiltin(name string) string {
+	if c.glslProfile != "100" {
+		switch name {
+		case "texture2D", "textureCube":
+			return "texture"
+		case "texture2DProj":
+			return "textureProj"
+		case "texture2DLod", "textureCubeLod":
+			return "textureLod"
+		case "gl_FragColor":
+			return "gx_FragColor" // Declared as an `out` by fragment shaders without `outputs`
+		}
+	}
+	return name
+}
+
+func glslStorageType(typ types.Type) *types.Named {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem() // Vertex shaders may take `varyings` by pointer to write them
+	}
+	named, _ := typ.(*types.Named)
+	return named
+}
+
+func glslParamType(shader types.Object, name string) *types.Named {
+	params := shader.Type().(*types.Signature).Params()
+	for i, nParams := 0, params.Len(); i < nParams; i++ {
+		if param := params.At(i); param.Name() == name {
+			return glslStorageType(param.Type())
+		}
+	}
+	return nil
+}
+
+func (c *Compiler) genUniformInfos(shader types.Object, withForEach bool) string {
+	uniformsType := glslParamType(shader, "uniforms")
+	if uniformsType == nil {
+		return ""
+	}
+	structType, ok := uniformsType.Underlying().(*types.Struct)
+	if !ok {
+		return ""
+	}
+
+	// Flatten to the names the GL API sees: nested struct fields are `.Field`,
+	// array elements are `[i]`
+	type uniform struct {
+		name, typeExpr, valExpr string
+	}
+	var uniforms []uniform
+	prevTarget := c.target
+	c.target = GLSL
+	var visit func(name, valExpr string, typ types.Type, pos token.Pos)
+	visit = func(name, valExpr string, typ types.Type, pos token.Pos) {
+		switch typ := typ.(type) {
+		case *types.Named:
+			if _, ok := c.externs[GLSL][typ.Obj()]; !ok {
+				if structType, ok := typ.Underlying().(*types.Struct); ok {
+					for i, nFields := 0, structType.NumFields(); i < nFields; i++ {
+						field := structType.Field(i)
+						visit(name+"."+field.Name(), valExpr+"."+field.Name(), field.Type(), field.Pos())
+					}
+					return
+				}
+			}
+		case *types.Array:
+			for i := int64(0); i < typ.Len(); i++ {
+				index := "[" + strconv.FormatInt(i, 10) + "]"
+				visit(name+index, valExpr+index, typ.Elem(), pos)
+			}
+			return
+		}
+		uniforms = append(uniforms, uniform{name, trimFinalSpace(c.genTypeExpr(typ, pos)), valExpr})
+	}
+	for i, nFields := 0, structType.NumFields(); i < nFields; i++ {
+		field := structType.Field(i)
+		name := uniformsType.Obj().Name() + "_" + field.Name()
+		if ext, ok := c.externs[GLSL][field]; ok {
+			name = ext
+		}
+		visit(name, "val."+field.Name(), field.Type(), field.Pos())
+	}
+	c.target = prevTarget
+
+	builder := &strings.Builder{}
+	builder.WriteString("inline constexpr gx::UniformInfo ")
+	builder.WriteString(shader.Name())
+	builder.WriteString("Uniforms[] {\n")
+	for _, uniform := range uniforms {
+		builder.WriteString("  { \"")
+		builder.WriteString(uniform.name)
+		builder.WriteString("\", \"")
+		builder.WriteString(uniform.typeExpr)
+		builder.WriteString("\" },\n")
+	}
+	builder.WriteString("};\n")
+	if withForEach {
+		builder.WriteString("\ninline void forEachUniform(const ")
+		builder.WriteString(uniformsType.Obj().Name())
+		builder.WriteString(" &val, auto &&func) {\n")
+		for _, uniform := range uniforms {
+			builder.WriteString("  func(\"")
+			builder.WriteString(uniform.name)
+			builder.WriteString("\", ")
+			builder.WriteString(uniform.valExpr)
+			builder.WriteString(");\n")
+		}
+		builder.WriteString("}\n")
+	}
+	return builder.String()
+}
+
+// Size and alignment of GLSL built-in types in std430 storage buffers
+var std430ExternLayouts = map[string][2]int64{
+	"vec2":  {8, 8},
+	"vec3":  {12, 16},
+	"vec4":  {16, 16},
+	"uvec3": {12, 16},
+	"mat2":  {16, 8},
+	"mat3":  {48, 16},
+	"mat4":  {64, 16},
+}
+
+func (c *Compiler) std430Layout(typ types.Type, pos token.Pos) (size, align int64) {
+	if named, ok := typ.(*types.Named); ok {
+		if ext, ok := c.externs[GLSL][named.Obj()]; ok {
+			if layout, ok := std430ExternLayouts[ext]; ok {
+				return layout[0], layout[1]
+			}
+			c.errorf(pos, "%s can't be in a storage buffer", ext)
+			return 0, 1
+		}
+	}
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		switch typ.Kind() {
+		case types.Int, types.Int32, types.Uint, types.Uint32, types.Float32, types.Float64:
+			return 4, 4
+		case types.Bool:
+			// Not host-shareable in WGSL, and a different size in C++
+			c.errorf(pos, "bool can't be in a storage buffer, use a uint instead")
+			return 0, 1
+		}
+	case *types.Array:
+		elemSize, elemAlign := c.std430Layout(typ.Elem(), pos)
+		stride := (elemSize + elemAlign - 1) / elemAlign * elemAlign
+		return stride * typ.Len(), elemAlign
+	case *types.Struct:
+		_, size, align := c.std430StructLayout(typ, pos)
+		return size, align
+	}
+	c.errorf(pos, "%s can't be in a storage buffer", typ)
+	return 0, 1
+}
+
+func (c *Compiler) std430StructLayout(structType *types.Struct, pos token.Pos) (offsets []int64, size, align int64) {
+	align = 1
+	for i, nFields := 0, structType.NumFields(); i < nFields; i++ {
+		field := structType.Field(i)
+		fieldSize, fieldAlign := c.std430Layout(field.Type(), field.Pos())
+		size = (size + fieldAlign - 1) / fieldAlign * fieldAlign
+		offsets = append(offsets, size)
+		size += fieldSize
+		align = max(align, fieldAlign)
+	}
+	size = (size + align - 1) / align * align
+	return
+}
+
+// Static assertions that a storage buffer's element type has the same layout in
+// C++ as in shaders, so buffers can be uploaded as-is
+func (c *Compiler) genStorageLayoutAsserts(typ *types.Named, visited map[*types.Named]bool) string {
+	if visited[typ] {
+		return ""
+	}
+	visited[typ] = true
+	structType, ok := typ.Underlying().(*types.Struct)
+	if _, isExtern := c.externs[GLSL][typ.Obj()]; !ok || isExtern {
+		return ""
+	}
+	builder := &strings.Builder{}
+	for i, nFields := 0, structType.NumFields(); i < nFields; i++ {
+		fieldType := structType.Field(i).Type()
+		for {
+			if arrayType, ok := fieldType.(*types.Array); ok {
+				fieldType = arrayType.Elem()
+			} else {
+				break
+			}
+		}
+		if named, ok := fieldType.(*types.Named); ok {
+			builder.WriteString(c.genStorageLayoutAsserts(named, visited))
+		}
+	}
+	offsets, size, _ := c.std430StructLayout(structType, typ.Obj().Pos())
+	typeExpr := trimFinalSpace(c.genTypeExpr(typ, typ.Obj().Pos()))
+	builder.WriteString("static_assert(sizeof(")
+	builder.WriteString(typeExpr)
+	builder.WriteString(") == ")
+	builder.WriteString(strconv.FormatInt(size, 10))
+	builder.WriteString(", \"")
+	builder.WriteString(typeExpr)
+	builder.WriteString(" must match its std430 layout\");\n")
+	for i, nFields := 0, structType.NumFields(); i < nFields; i++ {
+		field := structType.Field(i)
+		fieldName := field.Name()
+		if ext, ok := c.externs[CPP][field]; ok {
+			fieldName = ext
+		}
+		builder.WriteString("static_assert(offsetof(")
+		builder.WriteString(typeExpr)
+		builder.WriteString(", ")
+		builder.WriteString(fieldName)
+		builder.WriteString(") == ")
+		builder.WriteString(strconv.FormatInt(offsets[i], 10))
+		builder.WriteString(", \"")
+		builder.WriteString(typeExpr)
+		builder.WriteString(".")
+		builder.WriteString(fieldName)
+		builder.WriteString(" must match its std430 layout\");\n")
+	}
+	return builder.String()
+}
+
+//
+// WGSL
+//
+
+func (c *Compiler) wgslIsTexture(typ types.Type) bool {
+	if c.target == WGSL {
+		if named, ok := typ.(*types.Named); ok {
+			return strings.HasPrefix(c.externs[WGSL][named.Obj()], "texture_")
+		}
+	}
+	return false
+}
+
+// Binding numbers shared by a shader's GLSL and WGSL outputs, counting up in
+// parameter order: one per storage buffer, one per uniform parameter with
+// non-texture fields (WGSL's uniform block), and two per texture field, whose
+// WGSL sampler takes the number after it
+func (c *Compiler) shaderBindings(sig *types.Signature) map[*types.Var]int {
+	bindings := map[*types.Var]int{}
+	binding := 0
+	for i, nParams := 0, sig.Params().Len(); i < nParams; i++ {
+		param := sig.Params().At(i)
+		if c.storageBuffers[param] {
+			bindings[param] = binding
+			binding++
+			continue
+		}
+		paramType := glslStorageType(param.Type())
+		if glslStorageClass(param.Name()) != "uniform" || paramType == nil {
+			continue
+		}
+		structType, ok := paramType.Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		var textures []*types.Var
+		for fieldIndex := 0; fieldIndex < structType.NumFields(); fieldIndex++ {
+			field := structType.Field(fieldIndex)
+			if named, ok := field.Type().(*types.Named); ok && strings.HasPrefix(c.externs[WGSL][named.Obj()], "texture_") {
+				textures = append(textures, field)
+			}
+		}
+		if len(textures) < structType.NumFields() {
+			bindings[param] = binding
+			binding++
+		}
+		for _, texture := range textures {
+			bindings[texture] = binding
+			binding += 2
+		}
+	}
+	return bindings
+}
+
+// WGSL lacks some GLSL built-ins and scalar overloads, so GXSL maps them to
+// helper functions written after the shader body. Each is generated from the
+// WGSL type of its built-in's type argument, if any, and named after it like
+// `gx_mod_vec3f`.
+var wgslHelpers = map[string]func(typ string) string{
+	"gx_mod":       wgslGenericHelper("(x: $T, y: $T) -> $T", "x - y * floor(x / y)"),
+	"gx_modScalar": wgslGenericHelper("(x: $T, y: f32) -> $T", "x - y * floor(x / y)"),
+	"gx_minScalar": wgslGenericHelper("(x: $T, y: f32) -> $T", "min(x, $T(y))"),
+	"gx_maxScalar": wgslGenericHelper("(x: $T, y: f32) -> $T", "max(x, $T(y))"),
+	"gx_clampScalar": wgslGenericHelper("(x: $T, minVal: f32, maxVal: f32) -> $T",
+		"clamp(x, $T(minVal), $T(maxVal))"),
+	"gx_stepScalar": wgslGenericHelper("(edge: f32, x: $T) -> $T", "step($T(edge), x)"),
+	"gx_smoothstepScalar": wgslGenericHelper("(edge0: f32, edge1: f32, x: $T) -> $T",
+		"smoothstep($T(edge0), $T(edge1), x)"),
+	"gx_matrixCompMult": func(typ string) string {
+		cols := []string{}
+		for i := 0; i < int(typ[3]-'0'); i++ {
+			cols = append(cols, fmt.Sprintf("x[%d] * y[%d]", i, i))
+		}
+		return wgslGenericHelper("(x: $T, y: $T) -> $T", "$T("+strings.Join(cols, ", ")+")")(typ)
+	},
+	"gx_inverse": func(typ string) string {
+		switch typ[3] {
+		case '2':
+			return wgslGenericHelper("(m: $T) -> $T",
+				"$T(m[1][1], -m[0][1], -m[1][0], m[0][0]) * (1.0 / determinant(m))")(typ)
+		case '3':
+			return `(m: mat3x3<f32>) -> mat3x3<f32> {
+  let a = m[0];
+  let b = m[1];
+  let c = m[2];
+  let rows = mat3x3<f32>(cross(b, c), cross(c, a), cross(a, b));
+  return transpose(rows) * (1.0 / dot(a, cross(b, c)));
+}
+`
+		default:
+			// From Eric Lengyel's 'Foundations of Game Engine Development', treating
+			// each column as a 3D vector plus a fourth component
+			return `(m: mat4x4<f32>) -> mat4x4<f32> {
+  let a = m[0].xyz;
+  let b = m[1].xyz;
+  let c = m[2].xyz;
+  let d = m[3].xyz;
+  let x = m[0].w;
+  let y = m[1].w;
+  let z = m[2].w;
+  let w = m[3].w;
+  let invDet = 1.0 / (dot(cross(a, b), c * w - d * z) + dot(cross(c, d), a * y - b * x));
+  let s = cross(a, b) * invDet;
+  let t = cross(c, d) * invDet;
+  let u = (a * y - b * x) * invDet;
+  let v = (c * w - d * z) * invDet;
+  let rows = mat4x4<f32>(
+    vec4<f32>(cross(b, v) + t * y, -dot(b, t)),
+    vec4<f32>(cross(v, a) - t * x, dot(a, t)),
+    vec4<f32>(cross(d, u) + s * w, -dot(d, s)),
+    vec4<f32>(cross(u, c) - s * z, dot(c, s)),
+  );
+  return transpose(rows);
+}
+`
+		}
+	},
+	"gx_texture2DProj": func(string) string {
+		return `(t: texture_2d<f32>, s: sampler, coord: vec3<f32>) -> vec4<f32> {
+  return textureSample(t, s, coord.xy / coord.z);
+}
+`
+	},
+}
+
+func wgslGenericHelper(signature, result string) func(typ string) string {
+	return func(typ string) string {
+		return strings.ReplaceAll(signature+" {\n  return "+result+";\n}\n", "$T", typ)
+	}
+}
+
+// Name of the WGSL helper function to call for an extern, recording it to be
+// written with the shader, or the extern itself if it isn't a helper
+func (c *Compiler) wgslHelper(ext string, ident *ast.Ident) string {
+	genHelper, ok := wgslHelpers[ext]
+	if !ok {
+		return ext
+	}
+	typ, name := "", ext
+	if typeArgs := c.types.Instances[ident].TypeArgs; typeArgs != nil && typeArgs.Len() > 0 {
+		typ = trimFinalSpace(c.genTypeExpr(typeArgs.At(0), ident.Pos()))
+		name += "_" + strings.ReplaceAll(typ, "<f32>", "f")
+	}
+	if _, ok := c.wgslHelperDefns[name]; !ok {
+		c.wgslHelperDefns[name] = "fn " + name + genHelper(typ)
+	}
+	return name
+}
+
 //
 // Top-level
 //
 
 func (c *Compiler) compile() {
 	// Initialize maps
-	c.externs = map[Target]map[types.Object]string{CPP: {}, GLSL: {}}
+	c.externs = map[Target]map[types.Object]string{CPP: {}, GLSL: {}, WGSL: {}}
 	c.fieldIndices = map[*types.Var]int{}
 	c.methodRenames = map[types.Object]string{}
 	c.methodFieldTags = map[types.Object]string{}
-	c.genTypeExprs = map[Target]map[types.Type]string{CPP: {}, GLSL: {}}
+	c.initFuncNames = map[*ast.FuncDecl]string{}
+	c.constValueExprs = map[types.Object]ast.Expr{}
+	c.arrayLenExprs = map[*types.Array]ast.Expr{}
+	c.enumValues = map[types.Object][]*types.Const{}
+	c.typeAttribs = map[types.Object]string{}
+	c.layoutTypes = map[types.Object]bool{}
+	c.fieldDefaults = map[*types.Var]ast.Expr{}
+	c.reflectedMethods = map[types.Object][]reflectedMethod{}
+	c.storageBuffers = map[*types.Var]bool{}
+	c.anonStructTypeSpecs = map[*ast.StructType]*ast.TypeSpec{}
+	c.genTypeExprs = map[Target]map[types.Type]string{CPP: {}, GLSL: {}, WGSL: {}}
 	c.genTypeDecls = map[*ast.TypeSpec]string{}
-	c.genTypeDefns = map[Target]map[*ast.TypeSpec]string{CPP: {}, GLSL: {}}
+	c.genTypeDefns = map[Target]map[*ast.TypeSpec]string{CPP: {}, GLSL: {}, WGSL: {}}
 	c.genTypeMetas = map[*ast.TypeSpec]string{}
-	c.genFuncDecls = map[Target]map[*ast.FuncDecl]string{CPP: {}, GLSL: {}}
+	c.genMethodMetas = map[*ast.TypeSpec]string{}
+	c.genFuncDecls = map[Target]map[*ast.FuncDecl]string{CPP: {}, GLSL: {}, WGSL: {}}
 
 	// Initialize builders
 	c.errors = &strings.Builder{}
 	c.outputCC = &strings.Builder{}
 	c.outputHH = &strings.Builder{}
-	c.outputGLSLs = map[string]*strings.Builder{}
+	c.outputGLSLs = map[string]*ShaderOutput{}
+	c.outputWGSLs = map[string]*ShaderOutput{}
+	c.outputGos = map[string]string{}
 
 	// Load main package
 	packagesConfig := &packages.Config{
-		Mode: packages.NeedImports | packages.NeedDeps |
+		Mode: packages.NeedImports | packages.NeedDeps | packages.NeedModule |
 			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
 	}
+	buildTags := "gx" // Excludes generated Go-only sources, such as `goDefaultsFileName`
+	if c.buildTags != "" {
+		buildTags += "," + c.buildTags
+	}
+	packagesConfig.BuildFlags = []string{"-tags=" + buildTags}
 	loadPkgs, err := packages.Load(packagesConfig, c.mainPkgPath)
 	if err != nil {
 		fmt.Fprintln(c.errors, err)
@@ -1232,6 +3314,7 @@ func (c *Compiler) compile() {
 			visit(pkg)
 		}
 	}
+	initPkgs := append([]*packages.Package{}, pkgs...) // Initialization needs dependency order
 	sort.Slice(pkgs, func(i, j int) bool {
 		return pkgs[i].ID < pkgs[j].ID
 	})
@@ -1270,15 +3353,89 @@ func (c *Compiler) compile() {
 		}
 	}
 
+	// Collect anonymous struct types, sharing a name among identical ones
+	{
+		namedStructTypes := map[*ast.StructType]bool{}
+		anonStructNames := map[string]bool{}
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Syntax {
+				ast.Inspect(file, func(node ast.Node) bool {
+					switch node := node.(type) {
+					case *ast.TypeSpec:
+						if structType, ok := node.Type.(*ast.StructType); ok && !node.Assign.IsValid() {
+							namedStructTypes[structType] = true
+						}
+					case *ast.StructType:
+						c.collectFieldDefaults(pkg.Types, node)
+						if namedStructTypes[node] {
+							return true
+						}
+						typ, ok := c.types.TypeOf(node).(*types.Struct)
+						if !ok {
+							return true
+						}
+						for _, anonTypeSpec := range c.anonStructTypeSpecList {
+							if types.Identical(typ, c.types.TypeOf(anonTypeSpec.Type)) {
+								c.anonStructTypeSpecs[node] = anonTypeSpec
+								return true
+							}
+						}
+						ast.Inspect(node, func(node ast.Node) bool {
+							if ident, ok := node.(*ast.Ident); ok {
+								if typeName, ok := c.types.Uses[ident].(*types.TypeName); ok && typeName.Pkg() != nil &&
+									typeName.Parent() != typeName.Pkg().Scope() {
+									c.errorf(ident.Pos(), "anonymous struct referring to local type %s not supported", ident.Name)
+								}
+							}
+							return true
+						})
+						hash := fnv.New32a()
+						hash.Write([]byte(types.TypeString(typ, nil)))
+						name := fmt.Sprintf("gx__Struct%08x", hash.Sum32())
+						for i := 2; anonStructNames[name]; i++ {
+							name = fmt.Sprintf("gx__Struct%08x_%d", hash.Sum32(), i)
+						}
+						anonStructNames[name] = true
+						anonTypeSpec := &ast.TypeSpec{Name: &ast.Ident{NamePos: node.Pos(), Name: name}, Type: node}
+						c.types.Defs[anonTypeSpec.Name] = types.NewTypeName(node.Pos(), pkg.Types, name, typ)
+						c.anonStructTypeSpecs[node] = anonTypeSpec
+						c.anonStructTypeSpecList = append(c.anonStructTypeSpecList, anonTypeSpec)
+					}
+					return true
+				})
+			}
+		}
+	}
+
 	// Collect exports, externs and GXSL shaders
 	exports := map[types.Object]bool{}
-	gxslShaders := map[types.Object]bool{}
+	gxslShaders := map[types.Object]string{}
+	gxslShaderProfiles := map[types.Object]string{}
+	gxslShaderVariants := map[types.Object][][]ShaderVariantValue{} // Values for each variant constant
+	gxslWorkgroupSizes := map[types.Object][3]int{}
+	markedEnums := map[types.Object]bool{}
+	var typeRegistrations []typeRegistration
+	markedEnumPkgs := map[*types.Package]bool{} // Packages with `//gx:enum`, whose other types aren't enums
 	{
 		exportRe := regexp.MustCompile(`//gx:export`)
+		fieldAttribsRe := regexp.MustCompile(`^//gx:fieldattribs$`)
+		typeAttribsRe := regexp.MustCompile(`^//gx:typeattribs$`)
+		methodAttribsRe := regexp.MustCompile(`^//gx:methodattribs$`)
+		reflectRe := regexp.MustCompile(`^//gx:reflect(?: (.*))?$`)
+		registerRe := regexp.MustCompile(`^//gx:register(?:\s|$)`)
+		registerArgsRe := regexp.MustCompile(`^//gx:register (\w+) when embeds (\S+)\.(\w+)$`)
+		attribsRe := regexp.MustCompile(`^//gx:attribs (.*)$`)
+		enumRe := regexp.MustCompile(`^//gx:enum$`)
+		layoutRe := regexp.MustCompile(`^//gx:layout$`)
 		externsRe := regexp.MustCompile(`//gx:externs (.*)`)
 		externRe := regexp.MustCompile(`//gx:extern (.*)`)
-		gxslShaderRe := regexp.MustCompile(`//gxsl:shader`)
+		gxslShaderRe := regexp.MustCompile(`^//gxsl:(shader|vertex|fragment|compute)$`)
+		gxslProgramRe := regexp.MustCompile(`^//gxsl:program (\S+) (\S+)$`)
+		gxslProfileRe := regexp.MustCompile(`^//gxsl:profile (\S+)$`)
+		gxslVariantRe := regexp.MustCompile(`^//gxsl:variant (\w+)=(\S+)$`)
+		gxslWorkgroupRe := regexp.MustCompile(`^//gxsl:workgroup (\d+)(?: (\d+))?(?: (\d+))?$`)
 		gxslExternRe := regexp.MustCompile(`//gxsl:extern (.*)`)
+		wgslExternRe := regexp.MustCompile(`//wgsl:extern (.*)`)
 		parseDirective := func(re *regexp.Regexp, doc *ast.CommentGroup) string {
 			if doc != nil {
 				for _, comment := range doc.List {
@@ -1294,6 +3451,19 @@ func (c *Compiler) compile() {
 				fileExt := ""
 				if len(file.Comments) > 0 {
 					fileExt = parseDirective(externsRe, file.Comments[0])
+					for _, comment := range file.Comments[0].List {
+						if registerRe.MatchString(comment.Text) {
+							if matches := registerArgsRe.FindStringSubmatch(comment.Text); matches == nil {
+								c.errorf(comment.Pos(), "malformed register directive, expected `//gx:register MACRO when embeds PkgPath.TypeName`")
+							} else if embedPkg := findImportedPackage(pkg.Types, matches[2]); embedPkg == nil {
+								c.errorf(comment.Pos(), "package %s is not imported", matches[2])
+							} else if _, ok := embedPkg.Scope().Lookup(matches[3]).(*types.TypeName); !ok {
+								c.errorf(comment.Pos(), "no type %s in package %s", matches[3], matches[2])
+							} else {
+								typeRegistrations = append(typeRegistrations, typeRegistration{macro: matches[1], embedded: embedPkg.Scope().Lookup(matches[3])})
+							}
+						}
+					}
 				}
 				for _, decl := range file.Decls {
 					switch decl := decl.(type) {
@@ -1302,17 +3472,64 @@ func (c *Compiler) compile() {
 						for _, spec := range decl.Specs {
 							switch spec := spec.(type) {
 							case *ast.TypeSpec:
-								extern := false
-								if specExt := parseDirective(externRe, spec.Doc); specExt != "" {
-									c.externs[CPP][c.types.Defs[spec.Name]] = specExt
-									extern = true
-								} else if declExt != "" {
-									c.externs[CPP][c.types.Defs[spec.Name]] = declExt
-									extern = true
-								} else if fileExt != "" {
-									c.externs[CPP][c.types.Defs[spec.Name]] = fileExt + spec.Name.String()
-									extern = true
-								}
+								for _, schema := range []struct {
+									kind string
+									re   *regexp.Regexp
+									typ  **types.Named
+								}{
+									{"field", fieldAttribsRe, &c.fieldAttribsType},
+									{"type", typeAttribsRe, &c.typeAttribsType},
+									{"method", methodAttribsRe, &c.methodAttribsType},
+								} {
+									if parseDirective(schema.re, decl.Doc) != "" || parseDirective(schema.re, spec.Doc) != "" {
+										named, _ := c.types.Defs[spec.Name].Type().(*types.Named)
+										if _, ok := c.types.Defs[spec.Name].Type().Underlying().(*types.Struct); !ok || named == nil {
+											c.errorf(spec.Pos(), "%s attribs type must be a struct", schema.kind)
+										} else if *schema.typ != nil {
+											c.errorf(spec.Pos(), "%s attribs type already declared as %s", schema.kind, (*schema.typ).Obj().Name())
+										} else {
+											*schema.typ = named
+										}
+									}
+								}
+								if parseDirective(layoutRe, decl.Doc) != "" || parseDirective(layoutRe, spec.Doc) != "" {
+									if _, ok := spec.Type.(*ast.StructType); !ok || spec.TypeParams != nil {
+										c.errorf(spec.Pos(), "layout directive only applies to non-generic struct types")
+									} else {
+										c.layoutTypes[c.types.Defs[spec.Name]] = true
+									}
+								}
+								attribs := parseDirective(attribsRe, spec.Doc)
+								if attribs == "" {
+									attribs = parseDirective(attribsRe, decl.Doc)
+								}
+								if attribs != "" {
+									if _, ok := spec.Type.(*ast.StructType); !ok {
+										c.errorf(spec.Pos(), "attribs directive only applies to struct types")
+									} else {
+										c.typeAttribs[c.types.Defs[spec.Name]] = attribs
+									}
+								}
+								if parseDirective(enumRe, decl.Doc) != "" || parseDirective(enumRe, spec.Doc) != "" {
+									obj := c.types.Defs[spec.Name]
+									if !isEnumCandidate(obj) || spec.TypeParams != nil {
+										c.errorf(spec.Pos(), "enum type must be a named integer type")
+									} else {
+										markedEnums[obj] = true
+										markedEnumPkgs[obj.Pkg()] = true
+									}
+								}
+								extern := false
+								if specExt := parseDirective(externRe, spec.Doc); specExt != "" {
+									c.externs[CPP][c.types.Defs[spec.Name]] = specExt
+									extern = true
+								} else if declExt != "" {
+									c.externs[CPP][c.types.Defs[spec.Name]] = declExt
+									extern = true
+								} else if fileExt != "" {
+									c.externs[CPP][c.types.Defs[spec.Name]] = fileExt + spec.Name.String()
+									extern = true
+								}
 								if extern {
 									if typ, ok := spec.Type.(*ast.StructType); ok {
 										for _, field := range typ.Fields.List {
@@ -1330,13 +3547,15 @@ func (c *Compiler) compile() {
 										}
 									}
 								}
-								if gxslExt := parseDirective(gxslExternRe, decl.Doc); gxslExt != "" {
-									c.externs[GLSL][c.types.Defs[spec.Name]] = gxslExt
-									if typ, ok := spec.Type.(*ast.StructType); ok {
-										for _, field := range typ.Fields.List {
-											for _, fieldName := range field.Names {
-												if unicode.IsUpper(rune(fieldName.String()[0])) {
-													c.externs[GLSL][c.types.Defs[fieldName]] = lowerFirst(fieldName.String())
+								for target, re := range map[Target]*regexp.Regexp{GLSL: gxslExternRe, WGSL: wgslExternRe} {
+									if shaderExt := parseDirective(re, decl.Doc); shaderExt != "" {
+										c.externs[target][c.types.Defs[spec.Name]] = shaderExt
+										if typ, ok := spec.Type.(*ast.StructType); ok {
+											for _, field := range typ.Fields.List {
+												for _, fieldName := range field.Names {
+													if unicode.IsUpper(rune(fieldName.String()[0])) {
+														c.externs[target][c.types.Defs[fieldName]] = lowerFirst(fieldName.String())
+													}
 												}
 											}
 										}
@@ -1353,13 +3572,15 @@ func (c *Compiler) compile() {
 												c.externs[CPP][c.types.Defs[fieldName]] = ext
 											}
 										}
-										gxslExt := parseDirective(gxslExternRe, field.Comment)
-										if gxslExt == "" {
-											gxslExt = parseDirective(gxslExternRe, field.Doc)
-										}
-										if gxslExt != "" {
-											for _, fieldName := range field.Names {
-												c.externs[GLSL][c.types.Defs[fieldName]] = gxslExt
+										for target, re := range map[Target]*regexp.Regexp{GLSL: gxslExternRe, WGSL: wgslExternRe} {
+											shaderExt := parseDirective(re, field.Comment)
+											if shaderExt == "" {
+												shaderExt = parseDirective(re, field.Doc)
+											}
+											if shaderExt != "" {
+												for _, fieldName := range field.Names {
+													c.externs[target][c.types.Defs[fieldName]] = shaderExt
+												}
 											}
 										}
 									}
@@ -1377,6 +3598,9 @@ func (c *Compiler) compile() {
 									if gxslExt := parseDirective(gxslExternRe, decl.Doc); gxslExt != "" {
 										c.externs[GLSL][c.types.Defs[name]] = gxslExt
 									}
+									if wgslExt := parseDirective(wgslExternRe, decl.Doc); wgslExt != "" {
+										c.externs[WGSL][c.types.Defs[name]] = wgslExt
+									}
 								}
 							}
 						}
@@ -1384,8 +3608,104 @@ func (c *Compiler) compile() {
 						if parseDirective(exportRe, decl.Doc) != "" {
 							exports[c.types.Defs[decl.Name]] = true
 						}
-						if parseDirective(gxslShaderRe, decl.Doc) != "" {
-							gxslShaders[c.types.Defs[decl.Name]] = true
+						if decl.Doc != nil {
+							for _, comment := range decl.Doc.List {
+								if matches := reflectRe.FindStringSubmatch(comment.Text); len(matches) > 0 {
+									c.collectReflectedMethod(decl, matches[1], comment.Pos())
+								}
+							}
+						}
+						if stage := parseDirective(gxslShaderRe, decl.Doc); stage == "shader" {
+							gxslShaders[c.types.Defs[decl.Name]] = "fragment"
+						} else if stage != "" {
+							gxslShaders[c.types.Defs[decl.Name]] = stage
+						}
+						if profile := parseDirective(gxslProfileRe, decl.Doc); profile != "" {
+							if !slices.Contains(glslProfiles, profile) {
+								c.errorf(decl.Pos(), "unknown GLSL profile %s (expected one of %s)", profile, strings.Join(glslProfiles, ", "))
+							}
+							gxslShaderProfiles[c.types.Defs[decl.Name]] = profile
+						}
+						if stage := gxslShaders[c.types.Defs[decl.Name]]; stage == "compute" {
+							workgroupSize := [3]int{}
+							if decl.Doc != nil {
+								for _, comment := range decl.Doc.List {
+									if matches := gxslWorkgroupRe.FindStringSubmatch(comment.Text); len(matches) == 4 {
+										for i, match := range matches[1:] {
+											workgroupSize[i] = 1
+											if match != "" {
+												workgroupSize[i], _ = strconv.Atoi(match)
+											}
+										}
+									}
+								}
+							}
+							if workgroupSize[0] == 0 {
+								c.errorf(decl.Pos(), "compute shader %s needs a `//gxsl:workgroup X [Y [Z]]` size", decl.Name.Name)
+							}
+							gxslWorkgroupSizes[c.types.Defs[decl.Name]] = workgroupSize
+
+							// Slices of structs are storage buffers
+							sig := c.types.Defs[decl.Name].Type().(*types.Signature)
+							for i, nParams := 0, sig.Params().Len(); i < nParams; i++ {
+								param := sig.Params().At(i)
+								if sliceType, ok := param.Type().(*types.Slice); ok {
+									elemType, ok := sliceType.Elem().(*types.Named)
+									if ok {
+										_, ok = elemType.Underlying().(*types.Struct)
+									}
+									if !ok {
+										c.errorf(param.Pos(), "storage buffer %s must be a slice of a named struct type", param.Name())
+									}
+									c.storageBuffers[param] = true
+								}
+							}
+						}
+						if decl.Doc != nil {
+							for _, comment := range decl.Doc.List {
+								matches := gxslVariantRe.FindStringSubmatch(comment.Text)
+								if len(matches) != 3 {
+									continue
+								}
+								constObj, ok := pkg.Types.Scope().Lookup(matches[1]).(*types.Const)
+								if !ok {
+									c.errorf(comment.Pos(), "%s is not a constant in package %s", matches[1], pkg.Types.Name())
+									continue
+								}
+								basic, _ := constObj.Type().Underlying().(*types.Basic)
+								var values []ShaderVariantValue
+								for _, valueStr := range strings.Split(matches[2], ",") {
+									typeAndValue, err := types.Eval(c.fileSet, pkg.Types, comment.Pos(), valueStr)
+									val := typeAndValue.Value
+									if err == nil && val != nil && basic != nil {
+										switch {
+										case basic.Info()&types.IsBoolean != 0:
+											if val.Kind() != constant.Bool {
+												val = nil
+											}
+										case basic.Info()&types.IsInteger != 0:
+											if val = constant.ToInt(val); val.Kind() != constant.Int || !c.constantFits(val, basic) {
+												val = nil
+											}
+										case basic.Info()&types.IsFloat != 0:
+											if val = constant.ToFloat(val); val.Kind() == constant.Unknown {
+												val = nil
+											}
+										default:
+											val = nil
+										}
+									} else {
+										val = nil
+									}
+									if val == nil {
+										c.errorf(comment.Pos(), "variant value %s is not a valid %s for constant %s", valueStr, constObj.Type(), matches[1])
+										break
+									}
+									values = append(values, ShaderVariantValue{name: matches[1], value: val})
+								}
+								obj := c.types.Defs[decl.Name]
+								gxslShaderVariants[obj] = append(gxslShaderVariants[obj], values)
+							}
 						}
 						if declExt := parseDirective(externRe, decl.Doc); declExt != "" {
 							c.externs[CPP][c.types.Defs[decl.Name]] = declExt
@@ -1395,6 +3715,91 @@ func (c *Compiler) compile() {
 						if gxslExt := parseDirective(gxslExternRe, decl.Doc); gxslExt != "" {
 							c.externs[GLSL][c.types.Defs[decl.Name]] = gxslExt
 						}
+						if wgslExt := parseDirective(wgslExternRe, decl.Doc); wgslExt != "" {
+							c.externs[WGSL][c.types.Defs[decl.Name]] = wgslExt
+						}
+					}
+				}
+			}
+		}
+
+		// Export shader uniform types so their descriptors in the '.gx.hh' can use them
+		var exportType func(typ types.Type)
+		exportType = func(typ types.Type) {
+			switch typ := typ.(type) {
+			case *types.Named:
+				if !exports[typ.Obj()] {
+					exports[typ.Obj()] = true
+					if structType, ok := typ.Underlying().(*types.Struct); ok {
+						for i, nFields := 0, structType.NumFields(); i < nFields; i++ {
+							exportType(structType.Field(i).Type())
+						}
+					}
+				}
+			case *types.Array:
+				exportType(typ.Elem())
+			}
+		}
+		for obj := range gxslShaders {
+			if uniformsType := glslParamType(obj, "uniforms"); uniformsType != nil {
+				exportType(uniformsType)
+			}
+		}
+		for param := range c.storageBuffers {
+			exportType(param.Type().(*types.Slice).Elem())
+		}
+
+		// WGSL externs default to GXSL externs, with GLSL's output built-ins
+		// mapped to private variables that entry points copy out
+		for obj, gxslExt := range c.externs[GLSL] {
+			if _, ok := c.externs[WGSL][obj]; !ok {
+				switch gxslExt {
+				case "gl_Position":
+					c.externs[WGSL][obj] = "gx_Position"
+				case "gl_FragColor":
+					c.externs[WGSL][obj] = "gx_FragColor"
+				default:
+					c.externs[WGSL][obj] = gxslExt
+				}
+			}
+		}
+
+		for obj := range gxslShaderVariants {
+			if _, ok := gxslShaders[obj]; !ok {
+				c.errorf(obj.Pos(), "%s has variants but is not a GXSL shader", obj.Name())
+			}
+		}
+
+		// Check that GXSL programs pair shaders with matching profiles and varyings
+		profileOf := func(obj types.Object) string {
+			if profile, ok := gxslShaderProfiles[obj]; ok {
+				return profile
+			}
+			return c.glslProfile
+		}
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Syntax {
+				for _, commentGroup := range file.Comments {
+					for _, comment := range commentGroup.List {
+						if matches := gxslProgramRe.FindStringSubmatch(comment.Text); len(matches) == 3 {
+							vertex := pkg.Types.Scope().Lookup(matches[1])
+							fragment := pkg.Types.Scope().Lookup(matches[2])
+							if gxslShaders[vertex] != "vertex" {
+								c.errorf(comment.Pos(), "%s is not a GXSL vertex shader", matches[1])
+							} else if gxslShaders[fragment] != "fragment" {
+								c.errorf(comment.Pos(), "%s is not a GXSL fragment shader", matches[2])
+							} else if profileOf(vertex) != profileOf(fragment) {
+								c.errorf(comment.Pos(), "vertex shader %s uses GLSL profile %s but fragment shader %s uses %s",
+									matches[1], profileOf(vertex), matches[2], profileOf(fragment))
+							} else if fragmentVaryings := glslParamType(fragment, "varyings"); fragmentVaryings != nil {
+								if vertexVaryings := glslParamType(vertex, "varyings"); vertexVaryings == nil {
+									c.errorf(comment.Pos(), "vertex shader %s has no varyings for fragment shader %s", matches[1], matches[2])
+								} else if !types.Identical(vertexVaryings, fragmentVaryings) {
+									c.errorf(comment.Pos(), "vertex shader %s varyings %s don't match fragment shader %s varyings %s",
+										matches[1], vertexVaryings.Obj().Name(), matches[2], fragmentVaryings.Obj().Name())
+								}
+							}
+						}
 					}
 				}
 			}
@@ -1406,23 +3811,45 @@ func (c *Compiler) compile() {
 	var valueSpecs []*ast.ValueSpec
 	var funcDecls []*ast.FuncDecl
 	var gxslShaderDecls []*ast.FuncDecl
-	behaviors := map[types.Object]bool{}
+	initFuncDecls := map[*types.Package][]*ast.FuncDecl{}
+	registrations := map[types.Object][]string{} // Macros to register each type with
 	objTypeSpecs := map[types.Object]*ast.TypeSpec{}
 	objValueSpecs := map[types.Object]*ast.ValueSpec{}
 	objFuncDecls := map[types.Object]*ast.FuncDecl{}
 	{
+		enumConsts := map[types.Object][]*types.Const{}
 		for _, pkg := range pkgs {
 			for _, file := range pkg.Syntax {
+				ast.Inspect(file, func(node ast.Node) bool {
+					if arrayType, ok := node.(*ast.ArrayType); ok && arrayType.Len != nil {
+						if typ, ok := c.types.TypeOf(arrayType).(*types.Array); ok {
+							c.arrayLenExprs[typ] = arrayType.Len
+						}
+					}
+					return true
+				})
 				for _, decl := range file.Decls {
 					switch decl := decl.(type) {
 					case *ast.GenDecl:
+						var constValues []ast.Expr
 						for _, spec := range decl.Specs {
 							switch spec := spec.(type) {
 							case *ast.TypeSpec:
 								objTypeSpecs[c.types.Defs[spec.Name]] = spec
 							case *ast.ValueSpec:
-								for _, name := range spec.Names {
+								if decl.Tok == token.CONST && len(spec.Values) > 0 {
+									constValues = spec.Values // Implicitly repeated by later specs
+								}
+								for i, name := range spec.Names {
 									objValueSpecs[c.types.Defs[name]] = spec
+									if decl.Tok == token.CONST && i < len(constValues) {
+										c.constValueExprs[c.types.Defs[name]] = constValues[i]
+									}
+									if constObj, ok := c.types.Defs[name].(*types.Const); ok {
+										if named, ok := constObj.Type().(*types.Named); ok && isEnumCandidate(named.Obj()) {
+											enumConsts[named.Obj()] = append(enumConsts[named.Obj()], constObj)
+										}
+									}
 								}
 							}
 						}
@@ -1432,8 +3859,69 @@ func (c *Compiler) compile() {
 				}
 			}
 		}
+		for obj, typeSpec := range objTypeSpecs {
+			if _, ok := c.externs[CPP][obj]; ok || typeSpec.TypeParams != nil || !isEnumCandidate(obj) {
+				continue
+			}
+			if markedEnums[obj] || (!markedEnumPkgs[obj.Pkg()] && len(enumConsts[obj]) > 0) {
+				c.enumValues[obj] = enumConsts[obj]
+			}
+		}
 		typeSpecVisited := map[*ast.TypeSpec]bool{}
 		valueSpecVisited := map[*ast.ValueSpec]bool{}
+		funcDeclVisited := map[*ast.FuncDecl]bool{}
+		var visitTypeSpec func(typeSpec *ast.TypeSpec, export bool)
+		visitTypeSpec = func(typeSpec *ast.TypeSpec, export bool) {
+			if _, ok := c.externs[CPP][c.types.Defs[typeSpec.Name]]; ok {
+				return
+			}
+			obj := c.types.Defs[typeSpec.Name]
+			visited := typeSpecVisited[typeSpec]
+			if visited && !(export && !exports[obj]) {
+				return
+			}
+			if !visited {
+				typeSpecVisited[typeSpec] = true
+				if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+					for _, field := range structType.Fields.List {
+						if field.Names == nil {
+							embeddedType := c.types.TypeOf(field.Type)
+							if ptr, ok := embeddedType.(*types.Pointer); ok {
+								embeddedType = ptr.Elem()
+							}
+							if named, ok := embeddedType.(*types.Named); ok {
+								for _, registration := range typeRegistrations {
+									if named.Origin().Obj() == registration.embedded && !slices.Contains(registrations[obj], registration.macro) {
+										registrations[obj] = append(registrations[obj], registration.macro)
+										export = true
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+			if export {
+				exports[obj] = true
+			}
+			ast.Inspect(typeSpec.Type, func(node ast.Node) bool {
+				switch node := node.(type) {
+				case *ast.Ident:
+					if typeSpec, ok := objTypeSpecs[c.types.Uses[node]]; ok {
+						visitTypeSpec(typeSpec, export)
+					}
+				case *ast.StructType:
+					if anonTypeSpec, ok := c.anonStructTypeSpecs[node]; ok && node != typeSpec.Type {
+						visitTypeSpec(anonTypeSpec, export)
+						return false
+					}
+				}
+				return true
+			})
+			if !visited {
+				typeSpecs = append(typeSpecs, typeSpec)
+			}
+		}
 		for _, pkg := range pkgs {
 			for _, file := range pkg.Syntax {
 				for _, decl := range file.Decls {
@@ -1442,60 +3930,32 @@ func (c *Compiler) compile() {
 						for _, spec := range decl.Specs {
 							switch spec := spec.(type) {
 							case *ast.TypeSpec:
-								var visitTypeSpec func(typeSpec *ast.TypeSpec, export bool)
-								visitTypeSpec = func(typeSpec *ast.TypeSpec, export bool) {
-									if _, ok := c.externs[CPP][c.types.Defs[typeSpec.Name]]; ok {
-										return
-									}
-									obj := c.types.Defs[typeSpec.Name]
-									visited := typeSpecVisited[typeSpec]
-									if visited && !(export && !exports[obj]) {
-										return
-									}
-									if !visited {
-										typeSpecVisited[typeSpec] = true
-										if structType, ok := typeSpec.Type.(*ast.StructType); ok {
-											for _, field := range structType.Fields.List {
-												if field.Names == nil {
-													if ident, ok := field.Type.(*ast.Ident); ok && ident.Name == "Behavior" {
-														behaviors[obj] = true
-														export = true
-													}
-												}
-											}
-										}
-									}
-									if export {
-										exports[obj] = true
-									}
-									ast.Inspect(typeSpec.Type, func(node ast.Node) bool {
+								visitTypeSpec(spec, false)
+							case *ast.ValueSpec:
+								var visitValueSpec func(valueSpec *ast.ValueSpec)
+								var visitValueDeps func(node ast.Node)
+								visitValueDeps = func(node ast.Node) {
+									// References through function bodies count as dependencies too
+									ast.Inspect(node, func(node ast.Node) bool {
 										if ident, ok := node.(*ast.Ident); ok {
-											if typeSpec, ok := objTypeSpecs[c.types.Uses[ident]]; ok {
-												visitTypeSpec(typeSpec, export)
+											if valueSpec, ok := objValueSpecs[c.types.Uses[ident]]; ok {
+												visitValueSpec(valueSpec)
+											} else if funcDecl, ok := objFuncDecls[c.types.Uses[ident]]; ok {
+												if !funcDeclVisited[funcDecl] {
+													funcDeclVisited[funcDecl] = true
+													visitValueDeps(funcDecl)
+												}
 											}
 										}
 										return true
 									})
-									if !visited {
-										typeSpecs = append(typeSpecs, typeSpec)
-									}
 								}
-								visitTypeSpec(spec, false)
-							case *ast.ValueSpec:
-								var visitValueSpec func(valueSpec *ast.ValueSpec)
 								visitValueSpec = func(valueSpec *ast.ValueSpec) {
 									if valueSpecVisited[valueSpec] {
 										return
 									}
 									valueSpecVisited[valueSpec] = true
-									ast.Inspect(valueSpec, func(node ast.Node) bool {
-										if ident, ok := node.(*ast.Ident); ok {
-											if valueSpec, ok := objValueSpecs[c.types.Uses[ident]]; ok {
-												visitValueSpec(valueSpec)
-											}
-										}
-										return true
-									})
+									visitValueDeps(valueSpec)
 									extern := false
 									for _, name := range valueSpec.Names {
 										if _, ok := c.externs[CPP][c.types.Defs[name]]; ok {
@@ -1510,19 +3970,31 @@ func (c *Compiler) compile() {
 							}
 						}
 					case *ast.FuncDecl:
+						if decl.Recv == nil && decl.Name.Name == "init" {
+							initFuncDecls[pkg.Types] = append(initFuncDecls[pkg.Types], decl)
+							c.initFuncNames[decl] = c.generateIdentifier("init")
+						}
 						if _, ok := c.externs[CPP][c.types.Defs[decl.Name]]; !ok {
-							if _, ok := gxslShaders[c.types.Defs[decl.Name]]; !ok {
-								funcDecls = append(funcDecls, decl)
-							} else {
+							_, isShader := gxslShaders[c.types.Defs[decl.Name]]
+							if isShader {
 								gxslShaderDecls = append(gxslShaderDecls, decl)
 							}
+							if !isShader || c.emitGXSLCPP {
+								funcDecls = append(funcDecls, decl)
+							}
 						}
 					}
 				}
 			}
 		}
+		for _, anonTypeSpec := range c.anonStructTypeSpecList {
+			visitTypeSpec(anonTypeSpec, false) // Only used in function bodies or signatures
+		}
 	}
 
+	// Go constructors applying `default` tags
+	c.genGoDefaults(pkgs)
+
 	// `#include`s
 	var includes string
 	{
@@ -1572,8 +4044,9 @@ func (c *Compiler) compile() {
 		for _, typeSpec := range typeSpecs {
 			if typeDefn := c.genTypeDefn(typeSpec); typeDefn != "" {
 				c.write("\n")
-				if behaviors[c.types.Defs[typeSpec.Name]] {
-					c.write("ComponentTypeListAdd(")
+				for _, macro := range registrations[c.types.Defs[typeSpec.Name]] {
+					c.write(macro)
+					c.write("(")
 					c.write(typeSpec.Name.String())
 					c.write(");\n")
 				}
@@ -1603,19 +4076,47 @@ func (c *Compiler) compile() {
 			c.write(";\n")
 		}
 
+		// Method meta
+		c.write("\n\n")
+		c.write("//\n// Method meta\n//\n")
+		for _, typeSpec := range typeSpecs {
+			if meta := c.genMethodMeta(typeSpec); meta != "" {
+				c.write("\n")
+				c.write(meta)
+				c.write("\n")
+			}
+		}
+
 		// Variables
 		c.write("\n\n")
 		c.write("//\n// Variables\n//\n\n")
 		for _, valueSpec := range valueSpecs {
 			for i, name := range valueSpec.Names {
-				if name.Obj.Kind == ast.Con {
-					c.write("constexpr ")
+				if name.Name == "_" {
+					continue
 				}
-				c.write(c.genTypeExpr(c.types.TypeOf(valueSpec.Names[i]), valueSpec.Pos()))
-				c.writeIdent(name)
-				if len(valueSpec.Values) > 0 {
+				obj := c.types.Defs[name]
+				if constObj, ok := obj.(*types.Const); ok {
+					typ := constObj.Type()
+					if basic, ok := typ.(*types.Basic); ok && basic.Info()&types.IsUntyped != 0 && !c.constantFits(constObj.Val(), typ) {
+						continue // Untyped constants may exceed the range of any C++ type, all uses are folded
+					}
+					if basic, ok := typ.Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+						c.write("constexpr const char *")
+					} else {
+						c.write("constexpr ")
+						c.write(c.genTypeExpr(typ, name.Pos()))
+					}
+					c.writeIdent(name)
 					c.write(" = ")
-					c.writeExpr(valueSpec.Values[i])
+					if valueExpr := c.constValueExprs[obj]; c.dependsOnExterns(valueExpr) {
+						c.writeExpr(valueExpr)
+					} else {
+						c.writeConstant(constObj.Val(), typ, name.Pos())
+					}
+				} else {
+					c.write(c.genTypeExpr(c.types.TypeOf(valueSpec.Names[i]), valueSpec.Pos()))
+					c.writeIdent(name)
 				}
 				c.write(";\n")
 			}
@@ -1629,10 +4130,55 @@ func (c *Compiler) compile() {
 				c.write("\n")
 				c.write(c.genFuncDecl(funcDecl))
 				c.write(" ")
-				c.writeBlockStmt(funcDecl.Body)
+				if obj := c.types.Defs[funcDecl.Name]; obj.Pkg().Name() == "main" && obj.Name() == "main" && funcDecl.Recv == nil {
+					c.write("{\n")
+					c.indent++
+					c.write("gx::initPackages();\n")
+					c.writeStmtList(funcDecl.Body.List)
+					c.indent--
+					c.write("}")
+				} else {
+					c.writeBlockStmt(funcDecl.Body)
+				}
 				c.write("\n")
 			}
 		}
+
+		// Package initialization
+		c.write("\n\n")
+		c.write("//\n// Package initialization\n//\n\n")
+		c.write("void gx::initPackages() {\n")
+		c.indent++
+		c.write("static auto initialized = false;\n")
+		c.write("if (initialized) {\n")
+		c.write("  return;\n")
+		c.write("}\n")
+		c.write("initialized = true;\n")
+		for _, pkg := range initPkgs {
+			for _, valueSpec := range valueSpecs {
+				if c.types.Defs[valueSpec.Names[0]].Pkg() != pkg.Types || valueSpec.Names[0].Obj.Kind == ast.Con {
+					continue
+				}
+				if len(valueSpec.Values) > 0 && len(valueSpec.Values) != len(valueSpec.Names) {
+					c.errorf(valueSpec.Pos(), "multi-value initialization unsupported")
+					continue
+				}
+				for i, value := range valueSpec.Values {
+					if name := valueSpec.Names[i]; name.Name != "_" {
+						c.writeIdent(name)
+						c.write(" = ")
+					}
+					c.writeExpr(value)
+					c.write(";\n")
+				}
+			}
+			for _, initFuncDecl := range initFuncDecls[pkg.Types] {
+				c.write(c.initFuncNames[initFuncDecl])
+				c.write("();\n")
+			}
+		}
+		c.indent--
+		c.write("}\n")
 	}
 
 	// Output '.hh'
@@ -1661,8 +4207,9 @@ func (c *Compiler) compile() {
 			if exports[c.types.Defs[typeSpec.Name]] {
 				if typeDefn := c.genTypeDefn(typeSpec); typeDefn != "" {
 					c.outputHH.WriteString("\n")
-					if behaviors[c.types.Defs[typeSpec.Name]] {
-						c.outputHH.WriteString("ComponentTypeListAdd(")
+					for _, macro := range registrations[c.types.Defs[typeSpec.Name]] {
+						c.outputHH.WriteString(macro)
+						c.outputHH.WriteString("(")
 						c.outputHH.WriteString(typeSpec.Name.String())
 						c.outputHH.WriteString(");\n")
 					}
@@ -1712,89 +4259,446 @@ func (c *Compiler) compile() {
 			}
 		}
 
+		// Method meta
+		c.outputHH.WriteString("\n\n")
+		c.outputHH.WriteString("//\n// Method meta\n//\n")
+		for _, typeSpec := range typeSpecs {
+			if exports[c.types.Defs[typeSpec.Name]] {
+				if meta := c.genMethodMeta(typeSpec); meta != "" {
+					c.outputHH.WriteString("\n")
+					c.outputHH.WriteString(meta)
+					c.outputHH.WriteString("\n")
+				}
+			}
+		}
+
+		// Shader uniforms
+		if len(gxslShaderDecls) > 0 {
+			c.outputHH.WriteString("\n\n")
+			c.outputHH.WriteString("//\n// Shader uniforms\n//\n")
+			visited := map[*types.Named]bool{}
+			defaultProfile := c.glslProfile
+			for _, gxslShaderDecl := range gxslShaderDecls {
+				obj := c.types.Defs[gxslShaderDecl.Name]
+				if profile, ok := gxslShaderProfiles[obj]; ok {
+					c.setGLSLProfile(profile)
+				}
+				uniformsType := glslParamType(obj, "uniforms")
+				if uniformInfos := c.genUniformInfos(obj, !visited[uniformsType]); uniformInfos != "" {
+					c.outputHH.WriteString("\n")
+					c.outputHH.WriteString(uniformInfos)
+				}
+				visited[uniformsType] = true
+				c.setGLSLProfile(defaultProfile)
+			}
+		}
+
+		// Shader storage buffers
+		if len(c.storageBuffers) > 0 {
+			c.outputHH.WriteString("\n\n")
+			c.outputHH.WriteString("//\n// Shader storage buffers\n//\n\n")
+			visited := map[*types.Named]bool{}
+			for _, gxslShaderDecl := range gxslShaderDecls {
+				sig := c.types.Defs[gxslShaderDecl.Name].Type().(*types.Signature)
+				for i, nParams := 0, sig.Params().Len(); i < nParams; i++ {
+					if param := sig.Params().At(i); c.storageBuffers[param] {
+						if elemType, ok := param.Type().(*types.Slice).Elem().(*types.Named); ok {
+							c.outputHH.WriteString(c.genStorageLayoutAsserts(elemType, visited))
+						}
+					}
+				}
+			}
+		}
+
 		// Closing `#ifndef GX_GENERATED_CC`
 		c.outputHH.WriteString("\n#endif\n")
 	}
 
-	// Output '.glsl's
+	// Each combination of a shader's variant constant values gets its own output,
+	// named with the values that aren't false, eg. 'shader_FOG_QUALITY_2'
+	type gxslShaderVariant struct {
+		decl      *ast.FuncDecl
+		name      string
+		values    []ShaderVariantValue
+		overrides map[types.Object]constant.Value
+	}
+	var gxslShaderVariantList []gxslShaderVariant
 	{
-		c.target = GLSL
+		valueNameReplacer := strings.NewReplacer("-", "neg", ".", "p", "+", "")
+		variantNames := map[string]bool{}
 		for _, gxslShaderDecl := range gxslShaderDecls {
-			c.output = &strings.Builder{}
-			c.outputGLSLs[gxslShaderDecl.Name.Name] = c.output
-
-			c.write("#version 100\nprecision mediump float;\n\n")
-
-			// Collect dependencies
-			mainParamTypeExprs := map[ast.Node]bool{}
-			for _, param := range gxslShaderDecl.Type.Params.List {
-				if len(param.Names) > 0 && glslStorageClass(param.Names[0].Name) != "" {
-					ast.Inspect(param.Type, func(node ast.Node) bool {
-						mainParamTypeExprs[node] = true
-						return true
-					})
+			obj := c.types.Defs[gxslShaderDecl.Name]
+			variants := []gxslShaderVariant{{decl: gxslShaderDecl, name: gxslShaderDecl.Name.Name}}
+			for _, values := range gxslShaderVariants[obj] {
+				var combined []gxslShaderVariant
+				for _, variant := range variants {
+					for _, value := range values {
+						name := variant.name
+						switch value.value.Kind() {
+						case constant.Bool:
+							if constant.BoolVal(value.value) {
+								name += "_" + value.name
+							}
+						case constant.Float:
+							f, _ := constant.Float64Val(value.value)
+							name += "_" + value.name + "_" + valueNameReplacer.Replace(strconv.FormatFloat(f, 'g', -1, 64))
+						default:
+							name += "_" + value.name + "_" + valueNameReplacer.Replace(value.value.ExactString())
+						}
+						combined = append(combined, gxslShaderVariant{
+							decl:   gxslShaderDecl,
+							name:   name,
+							values: append(slices.Clip(variant.values), value),
+						})
+					}
 				}
+				variants = combined
 			}
-			visited := map[ast.Node]bool{}
-			var typeSpecDeps []*ast.TypeSpec
-			var valueSpecDeps []*ast.ValueSpec
-			var funcDeclDeps []*ast.FuncDecl
-			var visitDeps func(node ast.Node)
-			visitTypeSpec := func(typeSpec *ast.TypeSpec, shouldAppend bool) {
-				if visited[typeSpec] {
-					return
+			for _, variant := range variants {
+				if variantNames[variant.name] {
+					c.errorf(gxslShaderDecl.Pos(), "shader variant name %s is used more than once", variant.name)
 				}
-				if _, ok := c.externs[GLSL][c.types.Defs[typeSpec.Name]]; ok {
-					return
-				}
-				visited[typeSpec] = true
-				visitDeps(typeSpec)
-				if shouldAppend {
-					typeSpecDeps = append(typeSpecDeps, typeSpec)
+				variantNames[variant.name] = true
+				variant.overrides = map[types.Object]constant.Value{}
+				for _, value := range variant.values {
+					variant.overrides[obj.Pkg().Scope().Lookup(value.name)] = value.value
 				}
+				gxslShaderVariantList = append(gxslShaderVariantList, variant)
+			}
+		}
+	}
+
+	// Collect the types, values and functions a GXSL shader depends on, in
+	// definition order, skipping externs of the current target
+	collectShaderDeps := func(gxslShaderDecl *ast.FuncDecl) (typeSpecDeps []*ast.TypeSpec, valueSpecDeps []*ast.ValueSpec, funcDeclDeps []*ast.FuncDecl) {
+		mainParamTypeExprs := map[ast.Node]bool{}
+		for _, param := range gxslShaderDecl.Type.Params.List {
+			if len(param.Names) > 0 && glslStorageClass(param.Names[0].Name) != "" {
+				ast.Inspect(param.Type, func(node ast.Node) bool {
+					mainParamTypeExprs[node] = true
+					return true
+				})
+			}
+		}
+		visited := map[ast.Node]bool{}
+		var visitDeps func(node ast.Node)
+		visitTypeSpec := func(typeSpec *ast.TypeSpec, shouldAppend bool) {
+			if visited[typeSpec] {
+				return
+			}
+			if _, ok := c.externs[c.target][c.types.Defs[typeSpec.Name]]; ok {
+				return
+			}
+			visited[typeSpec] = true
+			visitDeps(typeSpec)
+			if shouldAppend {
+				typeSpecDeps = append(typeSpecDeps, typeSpec)
+			}
+		}
+		visitValueSpec := func(valueSpec *ast.ValueSpec) {
+			if visited[valueSpec] {
+				return
 			}
-			visitValueSpec := func(valueSpec *ast.ValueSpec) {
-				if visited[valueSpec] {
+			visited[valueSpec] = true
+			visitDeps(valueSpec)
+			for _, name := range valueSpec.Names {
+				if _, ok := c.externs[c.target][c.types.Defs[name]]; ok {
 					return
 				}
-				visited[valueSpec] = true
-				visitDeps(valueSpec)
-				for _, name := range valueSpec.Names {
-					if _, ok := c.externs[GLSL][c.types.Defs[name]]; ok {
-						return
+			}
+			valueSpecDeps = append(valueSpecDeps, valueSpec)
+		}
+		visitFuncDecl := func(funcDecl *ast.FuncDecl) {
+			if visited[funcDecl] {
+				return
+			}
+			if _, ok := c.externs[c.target][c.types.Defs[funcDecl.Name]]; ok {
+				return
+			}
+			visited[funcDecl] = true
+			visitDeps(funcDecl)
+			if funcDecl != gxslShaderDecl {
+				funcDeclDeps = append(funcDeclDeps, funcDecl)
+			}
+		}
+		visitDeps = func(node ast.Node) {
+			ast.Inspect(node, func(node ast.Node) bool {
+				if ifStmt, ok := node.(*ast.IfStmt); ok {
+					if branch, ok := c.constantIfBranch(ifStmt); ok {
+						if branch != nil {
+							visitDeps(branch)
+						}
+						return false
 					}
 				}
-				valueSpecDeps = append(valueSpecDeps, valueSpec)
-			}
-			visitFuncDecl := func(funcDecl *ast.FuncDecl) {
-				if visited[funcDecl] {
-					return
+				if ident, ok := node.(*ast.Ident); ok {
+					if typeSpec, ok := objTypeSpecs[c.types.Uses[ident]]; ok {
+						_, isMainParamTypeExpr := mainParamTypeExprs[ident]
+						visitTypeSpec(typeSpec, !isMainParamTypeExpr)
+					} else if valueSpec, ok := objValueSpecs[c.types.Uses[ident]]; ok {
+						visitValueSpec(valueSpec)
+					} else if funcDecl, ok := objFuncDecls[c.types.Uses[ident]]; ok {
+						visitFuncDecl(funcDecl)
+					}
 				}
-				if _, ok := c.externs[GLSL][c.types.Defs[funcDecl.Name]]; ok {
-					return
+				return true
+			})
+		}
+		visitDeps(gxslShaderDecl)
+		return
+	}
+
+	// Check GXSL shaders for what GLSL and WGSL can't express, so errors are
+	// reported at '.gx.go' positions rather than by the driver
+	validated := map[*ast.FuncDecl]map[string]bool{} // Profiles each function was validated for
+	reportedRecursion := map[*ast.FuncDecl]bool{}
+	validateShader := func(gxslShaderDecl *ast.FuncDecl, funcDeclDeps []*ast.FuncDecl) {
+		funcDecls := append([]*ast.FuncDecl{gxslShaderDecl}, funcDeclDeps...)
+		isConstant := func(expr ast.Expr) bool {
+			return expr != nil && c.types.Types[expr].Value != nil
+		}
+		checkType := func(typ types.Type, pos token.Pos) bool {
+			switch typ := typ.(type) {
+			case *types.Basic:
+				if typ.Info()&types.IsString != 0 {
+					c.errorf(pos, "strings not supported in GXSL")
+					return false
 				}
-				visited[funcDecl] = true
-				visitDeps(funcDecl)
-				if funcDecl != gxslShaderDecl {
-					funcDeclDeps = append(funcDeclDeps, funcDecl)
-				}
-			}
-			visitDeps = func(node ast.Node) {
-				ast.Inspect(node, func(node ast.Node) bool {
-					if ident, ok := node.(*ast.Ident); ok {
-						if typeSpec, ok := objTypeSpecs[c.types.Uses[ident]]; ok {
-							_, isMainParamTypeExpr := mainParamTypeExprs[ident]
-							visitTypeSpec(typeSpec, !isMainParamTypeExpr)
-						} else if valueSpec, ok := objValueSpecs[c.types.Uses[ident]]; ok {
-							visitValueSpec(valueSpec)
-						} else if funcDecl, ok := objFuncDecls[c.types.Uses[ident]]; ok {
-							visitFuncDecl(funcDecl)
+			case *types.Slice:
+				c.errorf(pos, "slices not supported in GXSL")
+				return false
+			case *types.Pointer:
+				c.errorf(pos, "pointers not supported in GXSL")
+				return false
+			case *types.Map:
+				c.errorf(pos, "maps not supported in GXSL")
+				return false
+			}
+			return true
+		}
+		for _, funcDecl := range funcDecls {
+			if validated[funcDecl] == nil {
+				validated[funcDecl] = map[string]bool{}
+			}
+			if validated[funcDecl][c.glslProfile] {
+				continue
+			}
+			firstValidation := len(validated[funcDecl]) == 0
+			validated[funcDecl][c.glslProfile] = true
+
+			// Loops in GLSL 100 must have a constant-bounded index
+			if c.glslProfile == "100" && funcDecl.Body != nil {
+				ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+					forStmt, ok := node.(*ast.ForStmt)
+					if !ok {
+						return true
+					}
+					valid := false
+					if init, ok := forStmt.Init.(*ast.AssignStmt); ok && init.Tok == token.DEFINE && len(init.Lhs) == 1 && isConstant(init.Rhs[0]) {
+						if index, ok := init.Lhs[0].(*ast.Ident); ok {
+							indexObj := c.types.Defs[index]
+							isIndex := func(expr ast.Expr) bool {
+								ident, ok := expr.(*ast.Ident)
+								return ok && c.types.Uses[ident] == indexObj
+							}
+							if cond, ok := forStmt.Cond.(*ast.BinaryExpr); ok && isIndex(cond.X) && isConstant(cond.Y) {
+								switch cond.Op {
+								case token.LSS, token.LEQ, token.GTR, token.GEQ, token.EQL, token.NEQ:
+									switch post := forStmt.Post.(type) {
+									case *ast.IncDecStmt:
+										valid = isIndex(post.X)
+									case *ast.AssignStmt:
+										valid = (post.Tok == token.ADD_ASSIGN || post.Tok == token.SUB_ASSIGN) &&
+											isIndex(post.Lhs[0]) && isConstant(post.Rhs[0])
+									}
+								}
+							}
+							ast.Inspect(forStmt.Body, func(node ast.Node) bool {
+								switch node := node.(type) {
+								case *ast.AssignStmt:
+									for _, lhs := range node.Lhs {
+										if isIndex(lhs) {
+											valid = false
+										}
+									}
+								case *ast.IncDecStmt:
+									if isIndex(node.X) {
+										valid = false
+									}
+								}
+								return valid
+							})
+						}
+					}
+					if !valid {
+						c.errorf(forStmt.Pos(), "GLSL 100 loops must look like `for i := a; i < b; i++` with constant a and b, and not modify i in the body")
+					}
+					return true
+				})
+			}
+			if !firstValidation {
+				continue
+			}
+
+			// Signatures
+			sig := c.types.Defs[funcDecl.Name].Type().(*types.Signature)
+			for i, nParams := 0, sig.Params().Len(); i < nParams; i++ {
+				if param := sig.Params().At(i); funcDecl != gxslShaderDecl || glslStorageClass(param.Name()) == "" && !c.storageBuffers[param] {
+					checkType(param.Type(), param.Pos())
+				}
+			}
+			for i, nResults := 0, sig.Results().Len(); i < nResults; i++ {
+				result := sig.Results().At(i)
+				checkType(result.Type(), result.Pos())
+			}
+			if sig.Recv() != nil {
+				checkType(sig.Recv().Type(), sig.Recv().Pos())
+			}
+
+			// Statements and expressions
+			if funcDecl.Body != nil {
+				ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+					switch node := node.(type) {
+					case *ast.DeferStmt:
+						c.errorf(node.Pos(), "defer not supported in GXSL")
+					case *ast.RangeStmt:
+						c.errorf(node.Pos(), "range not supported in GXSL")
+						return false
+					case *ast.FuncLit:
+						c.errorf(node.Pos(), "function literals not supported in GXSL")
+						return false
+					case *ast.BasicLit, *ast.CompositeLit, *ast.UnaryExpr:
+						return checkType(c.types.TypeOf(node.(ast.Expr)), node.Pos())
+					case *ast.Ident:
+						if glslStorageClass(node.Name) == "" {
+							if obj, ok := c.types.ObjectOf(node).(*types.Var); ok && !c.storageBuffers[obj] {
+								return checkType(obj.Type(), node.Pos())
+							}
+						}
+					}
+					return true
+				})
+			}
+		}
+
+		// Recursion, following calls between the shader's functions
+		funcDeclsByObj := map[types.Object]*ast.FuncDecl{}
+		for _, funcDecl := range funcDecls {
+			funcDeclsByObj[c.types.Defs[funcDecl.Name]] = funcDecl
+		}
+		const visiting, done = 1, 2
+		state := map[*ast.FuncDecl]int{}
+		var path []string
+		var visit func(funcDecl *ast.FuncDecl)
+		visit = func(funcDecl *ast.FuncDecl) {
+			state[funcDecl] = visiting
+			path = append(path, funcDecl.Name.Name)
+			if funcDecl.Body != nil {
+				ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+					if call, ok := node.(*ast.CallExpr); ok {
+						fun := call.Fun
+						if sel, ok := fun.(*ast.SelectorExpr); ok {
+							fun = sel.Sel
+						}
+						if ident, ok := fun.(*ast.Ident); ok {
+							if callee, ok := funcDeclsByObj[c.types.Uses[ident]]; ok {
+								switch state[callee] {
+								case visiting:
+									if !reportedRecursion[callee] {
+										reportedRecursion[callee] = true
+										cycle := append(path[slices.Index(path, callee.Name.Name):], callee.Name.Name)
+										c.errorf(call.Pos(), "recursion not supported in GXSL (%s)", strings.Join(cycle, " -> "))
+									}
+								case 0:
+									visit(callee)
+								}
+							}
 						}
 					}
 					return true
 				})
 			}
-			visitDeps(gxslShaderDecl)
+			path = path[:len(path)-1]
+			state[funcDecl] = done
+		}
+		visit(gxslShaderDecl)
+	}
+
+	// Output '.glsl's
+	{
+		c.target = GLSL
+		defaultProfile := c.glslProfile
+		for _, variant := range gxslShaderVariantList {
+			gxslShaderDecl := variant.decl
+			obj := c.types.Defs[gxslShaderDecl.Name]
+			stage := gxslShaders[obj]
+			c.constOverrides = variant.overrides
+			c.output = &strings.Builder{}
+			outputGLSL := &ShaderOutput{
+				shader:  gxslShaderDecl.Name.Name,
+				stage:   stage,
+				pos:     c.fileSet.Position(gxslShaderDecl.Name.Pos()),
+				variant: variant.values,
+				params:  map[string][]ShaderParam{},
+				output:  c.output,
+			}
+			c.outputGLSLs[variant.name] = outputGLSL
+
+			if profile, ok := gxslShaderProfiles[obj]; ok {
+				c.setGLSLProfile(profile)
+			} else {
+				c.setGLSLProfile(defaultProfile)
+			}
+			outputGLSL.profile = c.glslProfile
+			switch c.glslProfile {
+			case "100":
+				c.write("#version 100\n")
+			case "300es":
+				c.write("#version 300 es\n")
+			case "330":
+				c.write("#version 330 core\n")
+			case "430":
+				c.write("#version 430 core\n")
+			}
+			if stage == "fragment" && (c.glslProfile == "100" || c.glslProfile == "300es") {
+				c.write("precision mediump float;\n")
+			}
+			c.write("\n")
+			if stage == "compute" {
+				if c.glslProfile != "430" {
+					c.errorf(gxslShaderDecl.Pos(), "compute shaders need GLSL profile 430")
+				}
+				workgroupSize := gxslWorkgroupSizes[obj]
+				outputGLSL.workgroupSize = workgroupSize
+				c.write(fmt.Sprintf("layout(local_size_x = %d, local_size_y = %d, local_size_z = %d) in;\n\n",
+					workgroupSize[0], workgroupSize[1], workgroupSize[2]))
+			}
+
+			typeSpecDeps, valueSpecDeps, funcDeclDeps := collectShaderDeps(gxslShaderDecl)
+			validateShader(gxslShaderDecl, funcDeclDeps)
+
+			// Vertex shaders must set `gl_Position`
+			if stage == "vertex" {
+				setsPosition := false
+				for _, funcDecl := range append([]*ast.FuncDecl{gxslShaderDecl}, funcDeclDeps...) {
+					ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+						if assignStmt, ok := node.(*ast.AssignStmt); ok {
+							for _, lhs := range assignStmt.Lhs {
+								if sel, ok := lhs.(*ast.SelectorExpr); ok {
+									lhs = sel.Sel // Qualified with package name
+								}
+								if ident, ok := lhs.(*ast.Ident); ok && c.externs[GLSL][c.types.Uses[ident]] == "gl_Position" {
+									setsPosition = true
+								}
+							}
+						}
+						return !setsPosition
+					})
+				}
+				if !setsPosition {
+					c.errorf(gxslShaderDecl.Name.Pos(), "vertex shader %s must set gl_Position", gxslShaderDecl.Name.Name)
+				}
+			}
 
 			// Types
 			for _, typeSpec := range typeSpecDeps {
@@ -1805,26 +4709,72 @@ func (c *Compiler) compile() {
 			}
 
 			// Main function parameters
-			obj := c.types.Defs[gxslShaderDecl.Name]
 			sig := obj.Type().(*types.Signature)
+			bindings := c.shaderBindings(sig)
+			hasOutputs := false
 			for i, nParams := 0, sig.Params().Len(); i < nParams; i++ {
 				param := sig.Params().At(i)
-				if storageClass := glslStorageClass(param.Name()); storageClass != "" {
-					if structType, ok := param.Type().Underlying().(*types.Struct); ok {
+				if c.storageBuffers[param] {
+					elemType := param.Type().(*types.Slice).Elem()
+					c.write("layout(std430, binding = ")
+					c.write(strconv.Itoa(bindings[param]))
+					c.write(") buffer gx_")
+					c.write(param.Name())
+					c.write("_buffer {\n")
+					c.indent++
+					c.write(c.genTypeExpr(elemType, param.Pos()))
+					c.write(param.Name())
+					c.write("[];\n")
+					c.indent--
+					c.write("};\n\n")
+					outputGLSL.params["buffer"] = append(outputGLSL.params["buffer"],
+						ShaderParam{name: param.Name(), typ: trimFinalSpace(c.genTypeExpr(elemType, param.Pos())) + "[]", binding: bindings[param]})
+				} else if storageClass := glslStorageClass(param.Name()); storageClass != "" {
+					if storageClass == "attribute" && stage != "vertex" {
+						c.errorf(param.Pos(), "only vertex shaders can have attributes")
+					}
+					if storageClass == "varying" && stage == "compute" {
+						c.errorf(param.Pos(), "compute shaders can't have varyings")
+					}
+					if storageClass == "output" {
+						hasOutputs = true
+						if stage != "fragment" {
+							c.errorf(param.Pos(), "only fragment shaders can have outputs")
+						} else if c.glslProfile == "100" {
+							c.errorf(param.Pos(), "outputs need GLSL profile 300es or later")
+						}
+					}
+					paramType := glslStorageType(param.Type())
+					if paramType == nil {
+						c.errorf(param.Pos(), "%s must be a named struct type", param.Name())
+					} else if structType, ok := paramType.Underlying().(*types.Struct); ok {
 						numFields := structType.NumFields()
 						for fieldIndex := 0; fieldIndex < numFields; fieldIndex++ {
 							field := structType.Field(fieldIndex)
-							c.write(storageClass)
-							c.write(" ")
-							c.write(c.genTypeExpr(field.Type(), field.Pos()))
-							if ext, ok := c.externs[GLSL][field]; ok {
-								c.write(ext)
-							} else {
-								c.write(param.Type().(*types.Named).Obj().Name())
-								c.write("_")
-								c.write(field.Name())
+							if storageClass == "output" {
+								c.write("layout(location = ")
+								c.write(strconv.Itoa(fieldIndex))
+								c.write(") ")
 							}
+							typeExpr := c.genTypeExpr(field.Type(), field.Pos())
+							name, ok := c.externs[GLSL][field]
+							if !ok {
+								name = paramType.Obj().Name() + "_" + field.Name()
+							}
+							c.write(c.glslQualifier(storageClass, stage))
+							c.write(" ")
+							c.write(typeExpr)
+							c.write(name)
 							c.write(";\n")
+							binding, ok := bindings[field]
+							if !ok {
+								binding, ok = bindings[param]
+							}
+							if !ok || storageClass != "uniform" {
+								binding = -1
+							}
+							outputGLSL.params[storageClass] = append(outputGLSL.params[storageClass],
+								ShaderParam{name: name, typ: trimFinalSpace(typeExpr), binding: binding})
 						}
 						if numFields > 0 {
 							c.write("\n")
@@ -1832,6 +4782,10 @@ func (c *Compiler) compile() {
 					}
 				}
 			}
+			if stage == "fragment" && !hasOutputs && c.glslProfile != "100" {
+				c.write("out vec4 gx_FragColor;\n\n")
+				outputGLSL.params["output"] = append(outputGLSL.params["output"], ShaderParam{name: "gx_FragColor", typ: "vec4", binding: -1})
+			}
 
 			// Variables
 			for _, valueSpec := range valueSpecDeps {
@@ -1839,7 +4793,10 @@ func (c *Compiler) compile() {
 					c.write("const ")
 					c.write(c.genTypeExpr(c.types.TypeOf(valueSpec.Names[i]), valueSpec.Pos()))
 					c.writeIdent(name)
-					if len(valueSpec.Values) > 0 {
+					if constObj, ok := c.types.Defs[name].(*types.Const); ok && !c.dependsOnExterns(c.constValueExprs[constObj]) {
+						c.write(" = ")
+						c.writeConstant(c.constObjValue(constObj), constObj.Type(), name.Pos())
+					} else if len(valueSpec.Values) > 0 {
 						c.write(" = ")
 						c.writeExpr(valueSpec.Values[i])
 					}
@@ -1865,6 +4822,286 @@ func (c *Compiler) compile() {
 			c.writeBlockStmt(gxslShaderDecl.Body)
 			c.write("\n")
 		}
+		c.setGLSLProfile(defaultProfile)
+		c.constOverrides = nil
+	}
+
+	// Output '.wgsl's
+	if c.emitWGSL {
+		c.target = WGSL
+		for _, variant := range gxslShaderVariantList {
+			gxslShaderDecl := variant.decl
+			obj := c.types.Defs[gxslShaderDecl.Name]
+			stage := gxslShaders[obj]
+			c.constOverrides = variant.overrides
+			c.wgslHelperDefns = map[string]string{}
+			c.output = &strings.Builder{}
+			c.outputWGSLs[variant.name] = &ShaderOutput{
+				shader:  gxslShaderDecl.Name.Name,
+				stage:   stage,
+				variant: variant.values,
+				output:  c.output,
+			}
+
+			typeSpecDeps, valueSpecDeps, funcDeclDeps := collectShaderDeps(gxslShaderDecl)
+
+			// Types
+			for _, typeSpec := range typeSpecDeps {
+				if typeDefn := c.genTypeDefn(typeSpec); typeDefn != "" {
+					c.write(typeDefn)
+					c.write("\n\n")
+				}
+			}
+
+			// Main function parameters become bindings and private variables. Uniforms
+			// go in a block with 16-byte aligned fields to satisfy uniform layout rules,
+			// except textures, which are bound alongside their samplers.
+			type entryField struct {
+				name, typeExpr, variable string
+			}
+			var inputs, outputs []entryField
+			hasOutputs := false
+			sig := obj.Type().(*types.Signature)
+			bindings := c.shaderBindings(sig)
+			writeBinding := func(binding int) {
+				c.write("@group(0) @binding(")
+				c.write(strconv.Itoa(binding))
+				c.write(") ")
+			}
+			for i, nParams := 0, sig.Params().Len(); i < nParams; i++ {
+				param := sig.Params().At(i)
+				if c.storageBuffers[param] {
+					writeBinding(bindings[param])
+					c.write("var<storage, read_write> ")
+					c.write(param.Name())
+					c.write(": array<")
+					c.write(trimFinalSpace(c.genTypeExpr(param.Type().(*types.Slice).Elem(), param.Pos())))
+					c.write(">;\n\n")
+					continue
+				}
+				storageClass := glslStorageClass(param.Name())
+				paramType := glslStorageType(param.Type())
+				if storageClass == "" || paramType == nil {
+					continue
+				}
+				structType, ok := paramType.Underlying().(*types.Struct)
+				if !ok {
+					continue
+				}
+				if storageClass == "output" {
+					hasOutputs = true
+				}
+				if storageClass == "uniform" {
+					var uniformFields []*types.Var
+					for fieldIndex := 0; fieldIndex < structType.NumFields(); fieldIndex++ {
+						if field := structType.Field(fieldIndex); !c.wgslIsTexture(field.Type()) {
+							uniformFields = append(uniformFields, field)
+						}
+					}
+					if len(uniformFields) > 0 {
+						c.write("struct gx_Uniforms {\n")
+						for _, field := range uniformFields {
+							c.write("  @align(16) ")
+							c.write(field.Name())
+							c.write(": ")
+							c.write(trimFinalSpace(c.genTypeExpr(field.Type(), field.Pos())))
+							c.write(",\n")
+						}
+						c.write("}\n\n")
+						writeBinding(bindings[param])
+						c.write("var<uniform> gx_uniforms: gx_Uniforms;\n")
+					}
+				}
+				for fieldIndex := 0; fieldIndex < structType.NumFields(); fieldIndex++ {
+					field := structType.Field(fieldIndex)
+					typeExpr := trimFinalSpace(c.genTypeExpr(field.Type(), field.Pos()))
+					variable := paramType.Obj().Name() + "_" + field.Name()
+					if ext, ok := c.externs[WGSL][field]; ok {
+						variable = ext
+					}
+					switch {
+					case storageClass == "uniform" && c.wgslIsTexture(field.Type()):
+						writeBinding(bindings[field])
+						c.write("var ")
+						c.write(variable)
+						c.write(": ")
+						c.write(typeExpr)
+						c.write(";\n")
+						writeBinding(bindings[field] + 1)
+						c.write("var ")
+						c.write(variable)
+						c.write("_sampler: sampler;\n")
+						continue
+					case storageClass == "uniform":
+						continue
+					case storageClass == "attribute" || (storageClass == "varying" && stage == "fragment"):
+						inputs = append(inputs, entryField{field.Name(), typeExpr, variable})
+					default:
+						outputs = append(outputs, entryField{field.Name(), typeExpr, variable})
+					}
+					c.write("var<private> ")
+					c.write(variable)
+					c.write(": ")
+					c.write(typeExpr)
+					c.write(";\n")
+				}
+				if structType.NumFields() > 0 {
+					c.write("\n")
+				}
+			}
+			switch stage {
+			case "vertex":
+				c.write("var<private> gx_Position: vec4<f32>;\n\n")
+			case "fragment":
+				if !hasOutputs {
+					c.write("var<private> gx_FragColor: vec4<f32>;\n\n")
+					outputs = append(outputs, entryField{"FragColor", "vec4<f32>", "gx_FragColor"})
+				}
+			case "compute":
+				for _, builtin := range gxslComputeBuiltins {
+					c.write("var<private> gx_")
+					c.write(strings.TrimPrefix(builtin.glsl, "gl_"))
+					c.write(": ")
+					c.write(builtin.wgslType)
+					c.write(";\n")
+				}
+				c.write("\n")
+			}
+
+			// Variables
+			for _, valueSpec := range valueSpecDeps {
+				for i, name := range valueSpec.Names {
+					c.write("const ")
+					c.writeIdent(name)
+					c.write(": ")
+					c.write(trimFinalSpace(c.genTypeExpr(c.types.TypeOf(valueSpec.Names[i]), valueSpec.Pos())))
+					if constObj, ok := c.types.Defs[name].(*types.Const); ok && !c.dependsOnExterns(c.constValueExprs[constObj]) {
+						c.write(" = ")
+						c.writeConstant(c.constObjValue(constObj), constObj.Type(), name.Pos())
+					} else if len(valueSpec.Values) > 0 {
+						c.write(" = ")
+						c.writeExpr(valueSpec.Values[i])
+					}
+					c.write(";\n")
+				}
+			}
+			if len(valueSpecDeps) > 0 {
+				c.write("\n")
+			}
+
+			// Functions
+			for _, funcDecl := range funcDeclDeps {
+				if funcDecl.Body != nil {
+					c.write(c.genFuncDecl(funcDecl))
+					c.write(" ")
+					c.writeBlockStmt(funcDecl.Body)
+					c.write("\n\n")
+				}
+			}
+
+			// Shader body
+			c.write("fn gx_shader() ")
+			c.writeBlockStmt(gxslShaderDecl.Body)
+			c.write("\n\n")
+
+			// Helpers for built-ins WGSL lacks, used by the functions and body above
+			helperNames := []string{}
+			for name := range c.wgslHelperDefns {
+				helperNames = append(helperNames, name)
+			}
+			sort.Strings(helperNames)
+			for _, name := range helperNames {
+				c.write(c.wgslHelperDefns[name])
+				c.write("\n")
+			}
+
+			// Compute entry points copy built-ins to private variables
+			if stage == "compute" {
+				workgroupSize := gxslWorkgroupSizes[obj]
+				c.write(fmt.Sprintf("@compute @workgroup_size(%d, %d, %d)\nfn main(\n",
+					workgroupSize[0], workgroupSize[1], workgroupSize[2]))
+				c.indent++
+				for _, builtin := range gxslComputeBuiltins {
+					c.write("@builtin(")
+					c.write(builtin.wgsl)
+					c.write(") ")
+					c.write(builtin.wgsl)
+					c.write(": ")
+					c.write(builtin.wgslType)
+					c.write(",\n")
+				}
+				c.indent--
+				c.write(") {\n")
+				c.indent++
+				for _, builtin := range gxslComputeBuiltins {
+					c.write("gx_")
+					c.write(strings.TrimPrefix(builtin.glsl, "gl_"))
+					c.write(" = ")
+					c.write(builtin.wgsl)
+					c.write(";\n")
+				}
+				c.write("gx_shader();\n")
+				c.indent--
+				c.write("}\n")
+				continue
+			}
+
+			// Entry point copying between stage inputs / outputs and private variables
+			writeEntryStruct := func(name string, fields []entryField, position bool) {
+				c.write("struct ")
+				c.write(name)
+				c.write(" {\n")
+				c.indent++
+				if position {
+					c.write("@builtin(position) Position: vec4<f32>,\n")
+				}
+				for location, field := range fields {
+					c.write("@location(")
+					c.write(strconv.Itoa(location))
+					c.write(") ")
+					c.write(field.name)
+					c.write(": ")
+					c.write(field.typeExpr)
+					c.write(",\n")
+				}
+				c.indent--
+				c.write("}\n\n")
+			}
+			if len(inputs) > 0 {
+				writeEntryStruct("gx_Input", inputs, false)
+			}
+			writeEntryStruct("gx_Output", outputs, stage == "vertex")
+			c.write("@")
+			c.write(stage)
+			c.write("\nfn main(")
+			if len(inputs) > 0 {
+				c.write("gx_in: gx_Input")
+			}
+			c.write(") -> gx_Output {\n")
+			c.indent++
+			for _, input := range inputs {
+				c.write(input.variable)
+				c.write(" = gx_in.")
+				c.write(input.name)
+				c.write(";\n")
+			}
+			c.write("gx_shader();\n")
+			c.write("var gx_out: gx_Output;\n")
+			if stage == "vertex" {
+				c.write("gx_out.Position = gx_Position;\n")
+			}
+			for _, output := range outputs {
+				c.write("gx_out.")
+				c.write(output.name)
+				c.write(" = ")
+				c.write(output.variable)
+				c.write(";\n")
+			}
+			c.write("return gx_out;\n")
+			c.indent--
+			c.write("}\n")
+		}
+		c.constOverrides = nil
 	}
 }
 
@@ -1877,24 +5114,50 @@ var gxHH string
 
 func main() {
 	// Arguments
-	nArgs := len(os.Args)
-	if nArgs < 3 {
-		fmt.Println("usage: gx <main_package_path> <output_prefix> [glsl_output_prefix] [glsl_output_suffix]")
+	glslProfile := flag.String("glsl-profile", "100", "default GLSL profile for shaders ("+strings.Join(glslProfiles, ", ")+")")
+	emitWGSL := flag.Bool("wgsl", false, "also output WGSL for GXSL shaders")
+	emitGXSLCPP := flag.Bool("gxsl-cpp", false, "also output GXSL shaders as C++ functions, eg. for testing on the CPU")
+	buildTags := flag.String("tags", "", "comma-separated build tags to load packages with besides `gx`, eg. to select constants for shaders")
+	flag.Usage = func() {
+		fmt.Println("usage: gx [flags] <main_package_path> <output_prefix> [glsl_output_prefix] [glsl_output_suffix] [glsl_vertex_output_suffix] [glsl_compute_output_suffix]")
+		flag.PrintDefaults()
+		fmt.Println("\nMain module packages with `default` field tags also get a generated " + goDefaultsFileName + " with")
+		fmt.Println("`NewT()` constructors for code built by the Go toolchain, which is meant to be committed.")
+	}
+	flag.Parse()
+	args := flag.Args()
+	nArgs := len(args)
+	if nArgs < 2 || !slices.Contains(glslProfiles, *glslProfile) {
+		flag.Usage()
 		return
 	}
-	mainPkgPath := os.Args[1]
-	outputPrefix := os.Args[2]
+	mainPkgPath := args[0]
+	outputPrefix := args[1]
 	glslOutputPrefix := outputPrefix + "_"
-	if nArgs >= 4 {
-		glslOutputPrefix = os.Args[3]
+	if nArgs >= 3 {
+		glslOutputPrefix = args[2]
 	}
 	glslOutputSuffix := ".glsl"
+	if nArgs >= 4 {
+		glslOutputSuffix = args[3]
+	}
+	glslVertexOutputSuffix := glslOutputSuffix
 	if nArgs >= 5 {
-		glslOutputSuffix = os.Args[4]
+		glslVertexOutputSuffix = args[4]
+	}
+	glslComputeOutputSuffix := glslOutputSuffix
+	if nArgs >= 6 {
+		glslComputeOutputSuffix = args[5]
 	}
 
 	// Compile
-	c := Compiler{mainPkgPath: mainPkgPath}
+	c := Compiler{
+		mainPkgPath: mainPkgPath,
+		buildTags:   *buildTags,
+		glslProfile: *glslProfile,
+		emitWGSL:    *emitWGSL,
+		emitGXSLCPP: *emitGXSLCPP,
+	}
 	c.compile()
 
 	// Print output
@@ -1916,6 +5179,7 @@ func main() {
 				}
 			}
 		}
+		writeFailed := false
 		writeFileIfChanged := func(path string, contents string) {
 			byteContents := []byte(contents)
 			if f, err := os.Open(path); err == nil {
@@ -1924,13 +5188,149 @@ func main() {
 					return
 				}
 			}
-			os.WriteFile(path, byteContents, 0644)
+			if err := os.WriteFile(path, byteContents, 0644); err != nil {
+				fmt.Println(err)
+				writeFailed = true
+			}
 		}
 		writeFileIfChanged(filepath.Dir(outputPrefix)+"/gx.hh", gxHH)
 		writeFileIfChanged(outputPrefix+".gx.cc", c.outputCC.String())
 		writeFileIfChanged(outputPrefix+".gx.hh", c.outputHH.String())
+		for path, contents := range c.outputGos {
+			if contents == "" {
+				if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					fmt.Println(err)
+					writeFailed = true
+				}
+			} else {
+				writeFileIfChanged(path, contents)
+			}
+		}
+		// Manifest describing each shader output so asset pipelines can hot-reload
+		// changed shaders and validate bindings at load time. Buffers have their
+		// GLSL binding, and buffers and uniforms their WGSL group and binding, which
+		// is the uniform block's for non-texture uniforms. A texture's WGSL sampler
+		// is bound at the number after it.
+		type manifestParam struct {
+			Name        string `json:"name"`
+			Type        string `json:"type"`
+			GLSLBinding *int   `json:"glslBinding,omitempty"`
+			WGSLGroup   *int   `json:"wgslGroup,omitempty"`
+			WGSLBinding *int   `json:"wgslBinding,omitempty"`
+		}
+		type manifestFile struct {
+			Path string `json:"path"`
+			Hash string `json:"hash"`
+		}
+		type manifestShader struct {
+			Name       string                     `json:"name"`
+			Shader     string                     `json:"shader"`
+			Stage      string                     `json:"stage"`
+			Profile    string                     `json:"profile"`
+			Source     string                     `json:"source"`
+			Variant    map[string]json.RawMessage `json:"variant,omitempty"`
+			Uniforms   []manifestParam            `json:"uniforms"`
+			Attributes []manifestParam            `json:"attributes"`
+			Varyings   []manifestParam            `json:"varyings"`
+			Outputs    []manifestParam            `json:"outputs"`
+			Buffers    []manifestParam            `json:"buffers,omitempty"`
+			Workgroup  []int                      `json:"workgroup,omitempty"`
+			Samplers   []string                   `json:"samplers"`
+			GLSL       manifestFile               `json:"glsl"`
+			WGSL       *manifestFile              `json:"wgsl,omitempty"`
+		}
+		var manifest struct {
+			Shaders []*manifestShader `json:"shaders"`
+		}
+		writeShaderFile := func(path string, output *strings.Builder) manifestFile {
+			writeFileIfChanged(path, output.String())
+			hash := fnv.New64a()
+			hash.Write([]byte(output.String()))
+			return manifestFile{Path: path, Hash: fmt.Sprintf("%016x", hash.Sum64())}
+		}
+		manifestParams := func(params []ShaderParam, glslBound bool) []manifestParam {
+			result := []manifestParam{}
+			for _, param := range params {
+				entry := manifestParam{Name: param.name, Type: param.typ}
+				if param.binding >= 0 {
+					binding, group := param.binding, 0
+					if glslBound {
+						entry.GLSLBinding = &binding
+					}
+					if c.emitWGSL {
+						entry.WGSLGroup, entry.WGSLBinding = &group, &binding
+					}
+				}
+				result = append(result, entry)
+			}
+			return result
+		}
+		manifestShaders := map[string]*manifestShader{}
 		for name, outputGLSL := range c.outputGLSLs {
-			writeFileIfChanged(glslOutputPrefix+name+".gx"+glslOutputSuffix, outputGLSL.String())
+			suffix := glslOutputSuffix
+			switch outputGLSL.stage {
+			case "vertex":
+				suffix = glslVertexOutputSuffix
+			case "compute":
+				suffix = glslComputeOutputSuffix
+			}
+			source := outputGLSL.pos.Filename
+			if wd, err := os.Getwd(); err == nil {
+				if rel, err := filepath.Rel(wd, source); err == nil {
+					source = rel
+				}
+			}
+			shader := &manifestShader{
+				Name:       name,
+				Shader:     outputGLSL.shader,
+				Stage:      outputGLSL.stage,
+				Profile:    outputGLSL.profile,
+				Source:     fmt.Sprintf("%s:%d:%d", filepath.ToSlash(source), outputGLSL.pos.Line, outputGLSL.pos.Column),
+				Uniforms:   manifestParams(outputGLSL.params["uniform"], false),
+				Attributes: manifestParams(outputGLSL.params["attribute"], false),
+				Varyings:   manifestParams(outputGLSL.params["varying"], false),
+				Outputs:    manifestParams(outputGLSL.params["output"], false),
+				Buffers:    manifestParams(outputGLSL.params["buffer"], true),
+				Samplers:   []string{},
+				GLSL:       writeShaderFile(glslOutputPrefix+name+".gx"+suffix, outputGLSL.output),
+			}
+			if outputGLSL.stage == "compute" {
+				shader.Workgroup = outputGLSL.workgroupSize[:]
+			}
+			for _, value := range outputGLSL.variant {
+				if shader.Variant == nil {
+					shader.Variant = map[string]json.RawMessage{}
+				}
+				if value.value.Kind() == constant.Float {
+					f, _ := constant.Float64Val(value.value)
+					shader.Variant[value.name] = json.RawMessage(strconv.FormatFloat(f, 'g', -1, 64))
+				} else {
+					shader.Variant[value.name] = json.RawMessage(value.value.ExactString())
+				}
+			}
+			for _, uniform := range outputGLSL.params["uniform"] {
+				if strings.HasPrefix(uniform.typ, "sampler") {
+					shader.Samplers = append(shader.Samplers, uniform.name)
+				}
+			}
+			manifestShaders[name] = shader
+			manifest.Shaders = append(manifest.Shaders, shader)
+		}
+		for name, outputWGSL := range c.outputWGSLs {
+			wgsl := writeShaderFile(glslOutputPrefix+name+".gx.wgsl", outputWGSL.output)
+			if shader, ok := manifestShaders[name]; ok {
+				shader.WGSL = &wgsl
+			}
+		}
+		if len(manifest.Shaders) > 0 {
+			sort.Slice(manifest.Shaders, func(i, j int) bool {
+				return manifest.Shaders[i].Name < manifest.Shaders[j].Name
+			})
+			manifestJSON, _ := json.MarshalIndent(manifest, "", "  ")
+			writeFileIfChanged(glslOutputPrefix+"shaders.gx.json", string(manifestJSON)+"\n")
+		}
+		if writeFailed {
+			os.Exit(1)
 		}
 	}
 }
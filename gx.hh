@@ -1,9 +1,18 @@
 #pragma once
 
+#include <cerrno>
+#include <cmath>
+#include <concepts>
+#include <cstddef>
+#include <cstdint>
 #include <cstdio>
 #include <cstdlib>
 #include <cstring>
+#include <functional>
+#include <limits>
 #include <new>
+#include <numbers>
+#include <type_traits>
 #include <utility>
 
 
@@ -121,6 +130,16 @@ constexpr int len(const Array<T, N> &a) {
   return N;
 }
 
+template<typename T, int N>
+bool operator==(const Array<T, N> &a, const Array<T, N> &b) {
+  for (auto i = 0; i < N; ++i) {
+    if (!(a.data[i] == b.data[i])) {
+      return false;
+    }
+  }
+  return true;
+}
+
 
 //
 // Slice
@@ -351,6 +370,15 @@ template<typename T>
 Defer(T) -> Defer<T>;
 
 
+//
+// Package initialization
+//
+
+// Runs package variable initializers and `init()` functions in dependency
+// order. Called at the start of `main`, hosts without one call it explicitly.
+void initPackages();
+
+
 //
 // Meta
 //
@@ -366,5 +394,1806 @@ using FieldAttribs = GX_FIELD_ATTRIBS;
 template<typename T, int N>
 struct FieldTag {};
 
+#ifndef GX_METHOD_ATTRIBS
+struct MethodAttribs {
+  const char *name;
+};
+#else
+using MethodAttribs = GX_METHOD_ATTRIBS;
+#endif
+
+template<typename T, int N>
+struct MethodTag {};
+
+#ifndef GX_TYPE_ATTRIBS
+struct TypeAttribs {};
+#else
+using TypeAttribs = GX_TYPE_ATTRIBS;
+#endif
+
+// Specialized by the compiler for each struct, and each instantiation of
+// generic ones, with its Go `name`, `packagePath`, an `id` hashed from both and
+// the `attribs` from its `//gx:attribs` directive
+template<typename T>
+struct TypeInfo {};
+
+template<typename T>
+concept HasFields = requires(T &val) { forEachField(val, [](auto, auto &) {}); };
+
+// Specialized by the compiler with a hash of each struct's field names and
+// types, so data written with a different schema can be detected
+template<typename T>
+struct SchemaHash {};
+
+template<typename T>
+concept HasSchemaHash = requires { SchemaHash<T>::value; };
+
+
+//
+// Enum
+//
+
+// Base of named integer types that have constants, with their values listed by
+// a generated `forEachEnumValue(T, func)`. They convert to and from their
+// underlying integer like in Go, but are distinct types so functions like
+// `nameOf` can be overloaded on them.
+template<typename T>
+struct Enum {
+  using Underlying = T;
+  T value = 0;
+
+  constexpr Enum() = default;
+
+  constexpr Enum(T value_)
+      : value(value_) {
+  }
+
+  constexpr operator T() const {
+    return value;
+  }
+
+  Enum &operator++() {
+    ++value;
+    return *this;
+  }
+
+  Enum &operator--() {
+    --value;
+    return *this;
+  }
+
+  T operator++(int) {
+    return value++;
+  }
+
+  T operator--(int) {
+    return value--;
+  }
+
+  Enum &operator+=(T n) {
+    value += n;
+    return *this;
+  }
+
+  Enum &operator-=(T n) {
+    value -= n;
+    return *this;
+  }
+
+  Enum &operator*=(T n) {
+    value *= n;
+    return *this;
+  }
+
+  Enum &operator/=(T n) {
+    value /= n;
+    return *this;
+  }
+
+  Enum &operator%=(T n) {
+    value %= n;
+    return *this;
+  }
+
+  Enum &operator&=(T n) {
+    value &= n;
+    return *this;
+  }
+
+  Enum &operator|=(T n) {
+    value |= n;
+    return *this;
+  }
+
+  Enum &operator^=(T n) {
+    value ^= n;
+    return *this;
+  }
+
+  Enum &operator<<=(T n) {
+    value <<= n;
+    return *this;
+  }
+
+  Enum &operator>>=(T n) {
+    value >>= n;
+    return *this;
+  }
+};
+
+template<typename T>
+concept IsEnum = std::is_base_of_v<Enum<typename T::Underlying>, T>;
+
+// Name of the first constant with the value, or `nullptr` if there is none
+template<IsEnum T>
+const char *enumValueName(T val) {
+  const char *result = nullptr;
+  forEachEnumValue(val, [&](const char *name, T value) {
+    if (!result && value == val) {
+      result = name;
+    }
+  });
+  return result;
+}
+
+// Formats values without a constant like `Size(3)`, for generated `nameOf`s
+inline String unnamedEnumValue(const char *typeName, long long val) {
+  char buf[96];
+  std::snprintf(buf, sizeof(buf), "%s(%lld)", typeName, val);
+  return String(buf);
+}
+
+// Sets `val` to the constant named `name`, returning whether there was one
+template<IsEnum T>
+bool parseEnum(T &val, const char *name) {
+  auto found = false;
+  forEachEnumValue(val, [&](const char *valueName, T value) {
+    if (!found && std::strcmp(valueName, name) == 0) {
+      val = value;
+      found = true;
+    }
+  });
+  return found;
+}
+
+template<IsEnum T>
+bool parseEnum(T *val, const char *name) {
+  return parseEnum(deref(val), name);
+}
+
+template<IsEnum T>
+void print(T val) {
+  print((const char *)nameOf(val));
+}
+
+
+//
+// JSON
+//
+
+// Structs are objects keyed by the field names in their `forEachField`
+// metadata. Slices and arrays are arrays, and enums are the names of their
+// constants. Reading resets structs to their defaults first, so missing fields
+// get their `default:` values.
+
+template<typename T>
+struct IsSlice : std::false_type {};
+template<typename T>
+struct IsSlice<Slice<T>> : std::true_type {};
+
+template<typename T>
+struct IsArray : std::false_type {};
+template<typename T, int N>
+struct IsArray<Array<T, N>> : std::true_type {};
+
+struct JSONWriter {
+  Slice<char> out;
+
+  void write(const char *s) {
+    while (*s) {
+      append(out, *s++);
+    }
+  }
+
+  void writeString(const char *s) {
+    append(out, '"');
+    for (; *s; ++s) {
+      switch (*s) {
+      case '"':
+        write("\\\"");
+        break;
+      case '\\':
+        write("\\\\");
+        break;
+      case '\n':
+        write("\\n");
+        break;
+      case '\r':
+        write("\\r");
+        break;
+      case '\t':
+        write("\\t");
+        break;
+      default:
+        if ((unsigned char)*s < 0x20) {
+          char buf[8];
+          std::snprintf(buf, sizeof(buf), "\\u%04x", *s);
+          write(buf);
+        } else {
+          append(out, *s);
+        }
+      }
+    }
+    append(out, '"');
+  }
+
+  template<typename T>
+  void writeValue(const T &val) {
+    if constexpr (std::is_same_v<T, bool>) {
+      write(val ? "true" : "false");
+    } else if constexpr (IsEnum<T>) {
+      if (auto name = enumValueName(val)) {
+        writeString(name);
+      } else {
+        writeValue(val.value);
+      }
+    } else if constexpr (std::is_integral_v<T>) {
+      char buf[32];
+      if constexpr (std::is_signed_v<T>) {
+        std::snprintf(buf, sizeof(buf), "%lld", (long long)val);
+      } else {
+        std::snprintf(buf, sizeof(buf), "%llu", (unsigned long long)val);
+      }
+      write(buf);
+    } else if constexpr (std::is_floating_point_v<T>) {
+      if (!std::isfinite(val)) {
+        write("null"); // JSON has no infinities or NaNs, this reads back as NaN
+      } else {
+        char buf[32];
+        std::snprintf(buf, sizeof(buf), std::is_same_v<T, float> ? "%.9g" : "%.17g", double(val));
+        write(buf);
+      }
+    } else if constexpr (std::is_same_v<T, String>) {
+      writeString(val);
+    } else if constexpr (IsSlice<T>::value || IsArray<T>::value) {
+      append(out, '[');
+      for (auto i = 0; auto &elem : val) {
+        if (i++ > 0) {
+          append(out, ',');
+        }
+        writeValue(elem);
+      }
+      append(out, ']');
+    } else if constexpr (HasFields<T>) {
+      append(out, '{');
+      auto first = true;
+      forEachField(const_cast<T &>(val), [&](auto fieldTag, auto &fieldVal) {
+        if (!first) {
+          append(out, ',');
+        }
+        first = false;
+        writeString(fieldTag.attribs.name);
+        append(out, ':');
+        writeValue(fieldVal);
+      });
+      append(out, '}');
+    } else {
+      static_assert(!std::is_same_v<T, T>, "type can't be written as JSON");
+    }
+  }
+};
+
+template<typename T>
+String toJSON(const T &val) {
+  JSONWriter writer;
+  writer.writeValue(val);
+  append(writer.out, '\0');
+  String result;
+  result.slice = std::move(writer.out);
+  return result;
+}
+
+// Base of the JSON and binary readers, tracking the path to the value being
+// read to report errors like "$.enemies[2].speed: expected a number"
+struct PathReader {
+  Slice<char> path; // Like `.enemies[2].speed`, to the value being read
+  String error;
+
+  bool fail(const char *message) {
+    if (len(error) == 0) {
+      Slice<char> msg;
+      append(msg, '$');
+      for (auto c : path) {
+        append(msg, c);
+      }
+      append(msg, ':');
+      append(msg, ' ');
+      for (auto s = message; *s; ++s) {
+        append(msg, *s);
+      }
+      append(msg, '\0');
+      error.slice = std::move(msg);
+    }
+    return false;
+  }
+
+  void pushPath(const char *s) {
+    while (*s) {
+      append(path, *s++);
+    }
+  }
+
+  void popPath(int size) {
+    path.size = size;
+  }
+};
+
+struct JSONReader : PathReader {
+  const char *p;
+
+  void skipWhitespace() {
+    while (*p == ' ' || *p == '\t' || *p == '\n' || *p == '\r') {
+      ++p;
+    }
+  }
+
+  bool consume(char c) {
+    skipWhitespace();
+    if (*p == c) {
+      ++p;
+      return true;
+    }
+    return false;
+  }
+
+  bool consumeLiteral(const char *literal) {
+    skipWhitespace();
+    auto n = std::strlen(literal);
+    if (std::strncmp(p, literal, n) == 0) {
+      p += n;
+      return true;
+    }
+    return false;
+  }
+
+  bool readString(Slice<char> &out) {
+    if (!consume('"')) {
+      return fail("expected a string");
+    }
+    while (*p != '"') {
+      if (*p == '\0') {
+        return fail("unterminated string");
+      }
+      if (*p != '\\') {
+        append(out, *p++);
+        continue;
+      }
+      ++p;
+      switch (*p++) {
+      case '"':
+        append(out, '"');
+        break;
+      case '\\':
+        append(out, '\\');
+        break;
+      case '/':
+        append(out, '/');
+        break;
+      case 'b':
+        append(out, '\b');
+        break;
+      case 'f':
+        append(out, '\f');
+        break;
+      case 'n':
+        append(out, '\n');
+        break;
+      case 'r':
+        append(out, '\r');
+        break;
+      case 't':
+        append(out, '\t');
+        break;
+      case 'u': {
+        auto readHex = [&](unsigned &code) {
+          code = 0;
+          for (auto i = 0; i < 4; ++i) {
+            auto c = *p++;
+            code <<= 4;
+            if ('0' <= c && c <= '9') {
+              code |= c - '0';
+            } else if ('a' <= c && c <= 'f') {
+              code |= c - 'a' + 10;
+            } else if ('A' <= c && c <= 'F') {
+              code |= c - 'A' + 10;
+            } else {
+              return false;
+            }
+          }
+          return true;
+        };
+        unsigned code;
+        if (!readHex(code)) {
+          return fail("invalid unicode escape");
+        }
+        if (0xdc00 <= code && code < 0xe000) {
+          return fail("invalid unicode escape"); // Low surrogate without a high one
+        }
+        if (0xd800 <= code && code < 0xdc00) { // High surrogate, must be followed by a low one
+          unsigned low = 0;
+          if (p[0] == '\\' && p[1] == 'u') {
+            p += 2;
+            if (!readHex(low)) {
+              return fail("invalid unicode escape");
+            }
+          }
+          if (!(0xdc00 <= low && low < 0xe000)) {
+            return fail("invalid unicode escape");
+          }
+          code = 0x10000 + ((code - 0xd800) << 10) + (low - 0xdc00);
+        }
+        if (code < 0x80) {
+          append(out, char(code));
+        } else if (code < 0x800) {
+          append(out, char(0xc0 | (code >> 6)));
+          append(out, char(0x80 | (code & 0x3f)));
+        } else if (code < 0x10000) {
+          append(out, char(0xe0 | (code >> 12)));
+          append(out, char(0x80 | ((code >> 6) & 0x3f)));
+          append(out, char(0x80 | (code & 0x3f)));
+        } else {
+          append(out, char(0xf0 | (code >> 18)));
+          append(out, char(0x80 | ((code >> 12) & 0x3f)));
+          append(out, char(0x80 | ((code >> 6) & 0x3f)));
+          append(out, char(0x80 | (code & 0x3f)));
+        }
+        break;
+      }
+      default:
+        return fail("invalid escape in string");
+      }
+    }
+    ++p;
+    return true;
+  }
+
+  bool skipValue() {
+    skipWhitespace();
+    if (*p == '"') {
+      Slice<char> ignored;
+      return readString(ignored);
+    }
+    if (consume('[')) {
+      if (consume(']')) {
+        return true;
+      }
+      do {
+        if (!skipValue()) {
+          return false;
+        }
+      } while (consume(','));
+      return consume(']') || fail("expected ',' or ']'");
+    }
+    if (consume('{')) {
+      if (consume('}')) {
+        return true;
+      }
+      do {
+        Slice<char> ignored;
+        if (!readString(ignored) || !(consume(':') || fail("expected ':'")) || !skipValue()) {
+          return false;
+        }
+      } while (consume(','));
+      return consume('}') || fail("expected ',' or '}'");
+    }
+    if (consumeLiteral("true") || consumeLiteral("false") || consumeLiteral("null")) {
+      return true;
+    }
+    char *end;
+    std::strtod(p, &end);
+    if (end == p) {
+      return fail("expected a value");
+    }
+    p = end;
+    return true;
+  }
+
+  template<typename T>
+  bool readValue(T &val) {
+    skipWhitespace();
+    if constexpr (std::is_same_v<T, bool>) {
+      if (consumeLiteral("true")) {
+        val = true;
+      } else if (consumeLiteral("false")) {
+        val = false;
+      } else {
+        return fail("expected a boolean");
+      }
+      return true;
+    } else if constexpr (IsEnum<T>) {
+      if (*p != '"') {
+        return readValue(val.value);
+      }
+      Slice<char> name;
+      if (!readString(name)) {
+        return false;
+      }
+      append(name, '\0');
+      return parseEnum(val, name.data) || fail("unknown enum value");
+    } else if constexpr (std::is_integral_v<T>) {
+      auto start = p;
+      if (*p == '-') {
+        ++p;
+      }
+      if (!('0' <= *p && *p <= '9')) {
+        p = start;
+        return fail("expected an integer");
+      }
+      while ('0' <= *p && *p <= '9') {
+        ++p;
+      }
+      if (*p == '.' || *p == 'e' || *p == 'E') {
+        p = start;
+        return fail("expected an integer");
+      }
+      errno = 0;
+      if constexpr (std::is_signed_v<T>) {
+        auto n = std::strtoll(start, nullptr, 10);
+        if (errno == ERANGE || n < std::numeric_limits<T>::min() || n > std::numeric_limits<T>::max()) {
+          return fail("integer out of range");
+        }
+        val = T(n);
+      } else {
+        auto n = std::strtoull(start, nullptr, 10);
+        if (errno == ERANGE || *start == '-' || n > std::numeric_limits<T>::max()) {
+          return fail("integer out of range");
+        }
+        val = T(n);
+      }
+      return true;
+    } else if constexpr (std::is_floating_point_v<T>) {
+      if (consumeLiteral("null")) {
+        val = std::numeric_limits<T>::quiet_NaN();
+        return true;
+      }
+      char *end;
+      auto n = std::strtod(p, &end);
+      if (end == p || !(*p == '-' || ('0' <= *p && *p <= '9'))) {
+        return fail("expected a number");
+      }
+      p = end;
+      val = T(n);
+      return true;
+    } else if constexpr (std::is_same_v<T, String>) {
+      Slice<char> chars;
+      if (!readString(chars)) {
+        return false;
+      }
+      append(chars, '\0');
+      val.slice = std::move(chars);
+      return true;
+    } else if constexpr (IsSlice<T>::value || IsArray<T>::value) {
+      if (!consume('[')) {
+        return fail("expected an array");
+      }
+      val = T {};
+      if (consume(']')) {
+        return true;
+      }
+      auto pathSize = len(path);
+      auto i = 0;
+      do {
+        char index[16];
+        std::snprintf(index, sizeof(index), "[%d]", i);
+        pushPath(index);
+        if constexpr (IsSlice<T>::value) {
+          if (!readValue(append(val))) {
+            return false;
+          }
+        } else {
+          if (i >= len(val)) {
+            return fail("too many elements");
+          }
+          if (!readValue(val[i])) {
+            return false;
+          }
+        }
+        popPath(pathSize);
+        ++i;
+      } while (consume(','));
+      return consume(']') || fail("expected ',' or ']'");
+    } else if constexpr (HasFields<T>) {
+      if (!consume('{')) {
+        return fail("expected an object");
+      }
+      val = T {};
+      if (consume('}')) {
+        return true;
+      }
+      auto pathSize = len(path);
+      do {
+        Slice<char> key;
+        if (!readString(key)) {
+          return false;
+        }
+        append(key, '\0');
+        if (!consume(':')) {
+          return fail("expected ':'");
+        }
+        pushPath(".");
+        pushPath(key.data);
+        auto found = false, ok = true;
+        forEachField(val, [&](auto fieldTag, auto &fieldVal) {
+          if (!found && std::strcmp(fieldTag.attribs.name, key.data) == 0) {
+            found = true;
+            ok = readValue(fieldVal);
+          }
+        });
+        if (!(found ? ok : skipValue())) { // Unknown fields are skipped
+          return false;
+        }
+        popPath(pathSize);
+      } while (consume(','));
+      return consume('}') || fail("expected ',' or '}'");
+    } else {
+      static_assert(!std::is_same_v<T, T>, "type can't be read from JSON");
+    }
+  }
+};
+
+// Reads `val` from JSON, returning an error like "$.enemies[2].speed: expected
+// a number", or an empty string on success
+template<typename T>
+String fromJSON(T &val, const char *json) {
+  JSONReader reader { {}, json };
+  if (reader.readValue(val)) {
+    reader.skipWhitespace();
+    if (*reader.p != '\0') {
+      reader.fail("unexpected content after value");
+    }
+  }
+  return reader.error;
+}
+
+template<typename T>
+String fromJSON(T *val, const char *json) {
+  return fromJSON(deref(val), json);
+}
+
+
+//
+// Binary
+//
+
+// Data starts with the root type's `SchemaHash`. Integers are varints
+// (zigzag-encoded if signed), floats are little-endian, and strings, slices,
+// arrays and structs are length-prefixed. Struct fields are written as a
+// `binaryId << 3 | wireType` key followed by the value, so a reader can skip
+// fields it doesn't know about and leave missing fields at their defaults.
+// Field ids come from `attribs:"id=N"`, or else hash the field's name, so
+// fields can be reordered without breaking existing data.
+
+enum class WireType {
+  Varint = 0,
+  Fixed64 = 1,
+  Bytes = 2,
+  Fixed32 = 5,
+};
+
+template<typename T>
+constexpr WireType wireTypeOf() {
+  if constexpr (std::is_same_v<T, double>) {
+    return WireType::Fixed64;
+  } else if constexpr (std::is_same_v<T, float>) {
+    return WireType::Fixed32;
+  } else if constexpr (std::is_integral_v<T> || IsEnum<T>) {
+    return WireType::Varint;
+  } else {
+    return WireType::Bytes;
+  }
+}
+
+struct BinaryWriter {
+  Slice<std::uint8_t> out;
+
+  void writeVarint(std::uint64_t n) {
+    while (n >= 0x80) {
+      append(out, std::uint8_t(n | 0x80));
+      n >>= 7;
+    }
+    append(out, std::uint8_t(n));
+  }
+
+  void writeFixed(std::uint64_t n, int size) {
+    for (auto i = 0; i < size; ++i) {
+      append(out, std::uint8_t(n >> (8 * i)));
+    }
+  }
+
+  void writeBytes(const Slice<std::uint8_t> &bytes) {
+    writeVarint(len(bytes));
+    for (auto b : bytes) {
+      append(out, b);
+    }
+  }
+
+  template<typename T>
+  void writeValue(const T &val) {
+    if constexpr (std::is_same_v<T, bool>) {
+      writeVarint(val ? 1 : 0);
+    } else if constexpr (IsEnum<T>) {
+      writeValue(val.value);
+    } else if constexpr (std::is_integral_v<T>) {
+      if constexpr (std::is_signed_v<T>) {
+        auto n = std::int64_t(val);
+        writeVarint((std::uint64_t(n) << 1) ^ std::uint64_t(n >> 63));
+      } else {
+        writeVarint(val);
+      }
+    } else if constexpr (std::is_same_v<T, float>) {
+      std::uint32_t bits;
+      std::memcpy(&bits, &val, sizeof(bits));
+      writeFixed(bits, 4);
+    } else if constexpr (std::is_same_v<T, double>) {
+      std::uint64_t bits = 0;
+      std::memcpy(&bits, &val, sizeof(bits));
+      writeFixed(bits, 8);
+    } else if constexpr (std::is_same_v<T, String>) {
+      auto n = std::strlen(val);
+      writeVarint(n);
+      for (auto s = (const char *)val; *s; ++s) {
+        append(out, std::uint8_t(*s));
+      }
+    } else if constexpr (IsSlice<T>::value || IsArray<T>::value) {
+      BinaryWriter elems;
+      elems.writeVarint(len(val));
+      for (auto &elem : val) {
+        elems.writeValue(elem);
+      }
+      writeBytes(elems.out);
+    } else if constexpr (HasFields<T>) {
+      BinaryWriter fields;
+      forEachField(const_cast<T &>(val), [&](auto fieldTag, auto &fieldVal) {
+        using Field = std::remove_cvref_t<decltype(fieldVal)>;
+        fields.writeVarint(std::uint64_t(fieldTag.binaryId) << 3 | int(wireTypeOf<Field>()));
+        fields.writeValue(fieldVal);
+      });
+      writeBytes(fields.out);
+    } else {
+      static_assert(!std::is_same_v<T, T>, "type can't be written as binary");
+    }
+  }
+};
+
+template<typename T>
+Slice<std::uint8_t> toBinary(const T &val) {
+  static_assert(HasSchemaHash<T>, "binary data must have a struct at the root");
+  BinaryWriter writer;
+  writer.writeFixed(SchemaHash<T>::value, 8);
+  writer.writeValue(val);
+  return std::move(writer.out);
+}
+
+template<typename T>
+Slice<std::uint8_t> toBinary(T *val) {
+  return toBinary(deref(val));
+}
+
+struct BinaryReader : PathReader {
+  const std::uint8_t *p, *end;
+
+  bool readVarint(std::uint64_t &n) {
+    n = 0;
+    for (auto shift = 0; shift < 64; shift += 7) {
+      if (p == end) {
+        return fail("unexpected end of data");
+      }
+      auto b = *p++;
+      n |= std::uint64_t(b & 0x7f) << shift;
+      if (!(b & 0x80)) {
+        return true;
+      }
+    }
+    return fail("varint too long");
+  }
+
+  bool readFixed(std::uint64_t &n, int size) {
+    if (end - p < size) {
+      return fail("unexpected end of data");
+    }
+    n = 0;
+    for (auto i = 0; i < size; ++i) {
+      n |= std::uint64_t(*p++) << (8 * i);
+    }
+    return true;
+  }
+
+  // Reads a length prefix, returning the end of the bytes that follow it
+  bool readLength(const std::uint8_t *&bytesEnd) {
+    std::uint64_t n;
+    if (!readVarint(n)) {
+      return false;
+    }
+    if (n > std::uint64_t(end - p)) {
+      return fail("length out of range");
+    }
+    bytesEnd = p + n;
+    return true;
+  }
+
+  bool skipValue(WireType wireType) {
+    std::uint64_t ignored = 0;
+    switch (wireType) {
+    case WireType::Varint:
+      return readVarint(ignored);
+    case WireType::Fixed64:
+      return readFixed(ignored, 8);
+    case WireType::Fixed32:
+      return readFixed(ignored, 4);
+    case WireType::Bytes: {
+      const std::uint8_t *bytesEnd = nullptr;
+      if (!readLength(bytesEnd)) {
+        return false;
+      }
+      p = bytesEnd;
+      return true;
+    }
+    }
+    return fail("invalid wire type");
+  }
+
+  template<typename T>
+  bool readValue(T &val) {
+    if constexpr (std::is_same_v<T, bool>) {
+      std::uint64_t n = 0;
+      if (!readVarint(n)) {
+        return false;
+      }
+      if (n > 1) {
+        return fail("expected a boolean");
+      }
+      val = n == 1;
+      return true;
+    } else if constexpr (IsEnum<T>) {
+      return readValue(val.value);
+    } else if constexpr (std::is_integral_v<T>) {
+      std::uint64_t n = 0;
+      if (!readVarint(n)) {
+        return false;
+      }
+      if constexpr (std::is_signed_v<T>) {
+        auto m = std::int64_t(n >> 1) ^ -std::int64_t(n & 1);
+        if (m < std::numeric_limits<T>::min() || m > std::numeric_limits<T>::max()) {
+          return fail("integer out of range");
+        }
+        val = T(m);
+      } else {
+        if (n > std::numeric_limits<T>::max()) {
+          return fail("integer out of range");
+        }
+        val = T(n);
+      }
+      return true;
+    } else if constexpr (std::is_same_v<T, float>) {
+      std::uint64_t n = 0;
+      if (!readFixed(n, 4)) {
+        return false;
+      }
+      auto bits = std::uint32_t(n);
+      std::memcpy(&val, &bits, sizeof(bits));
+      return true;
+    } else if constexpr (std::is_same_v<T, double>) {
+      std::uint64_t bits = 0;
+      if (!readFixed(bits, 8)) {
+        return false;
+      }
+      std::memcpy(&val, &bits, sizeof(bits));
+      return true;
+    } else if constexpr (std::is_same_v<T, String>) {
+      const std::uint8_t *bytesEnd = nullptr;
+      if (!readLength(bytesEnd)) {
+        return false;
+      }
+      Slice<char> chars;
+      while (p < bytesEnd) {
+        append(chars, char(*p++));
+      }
+      append(chars, '\0');
+      val.slice = std::move(chars);
+      return true;
+    } else if constexpr (IsSlice<T>::value || IsArray<T>::value) {
+      const std::uint8_t *bytesEnd = nullptr;
+      std::uint64_t n = 0;
+      if (!readLength(bytesEnd) || !readVarint(n)) {
+        return false;
+      }
+      val = T {};
+      if constexpr (IsArray<T>::value) {
+        if (n > std::uint64_t(len(val))) {
+          return fail("too many elements");
+        }
+      }
+      auto pathSize = len(path);
+      for (auto i = 0; std::uint64_t(i) < n; ++i) {
+        char index[16];
+        std::snprintf(index, sizeof(index), "[%d]", i);
+        pushPath(index);
+        if (p >= bytesEnd) {
+          return fail("unexpected end of data");
+        }
+        if constexpr (IsSlice<T>::value) {
+          if (!readValue(append(val))) {
+            return false;
+          }
+        } else {
+          if (!readValue(val[i])) {
+            return false;
+          }
+        }
+        popPath(pathSize);
+      }
+      return p == bytesEnd || fail("unexpected data after elements");
+    } else if constexpr (HasFields<T>) {
+      const std::uint8_t *bytesEnd = nullptr;
+      if (!readLength(bytesEnd)) {
+        return false;
+      }
+      val = T {};
+      auto pathSize = len(path);
+      while (p < bytesEnd) {
+        std::uint64_t key;
+        if (!readVarint(key)) {
+          return false;
+        }
+        auto fieldId = key >> 3;
+        auto wireType = WireType(key & 7);
+        auto found = false, ok = true;
+        forEachField(val, [&](auto fieldTag, auto &fieldVal) {
+          if (!found && fieldTag.binaryId == fieldId) {
+            found = true;
+            pushPath(".");
+            pushPath(fieldTag.attribs.name);
+            using Field = std::remove_cvref_t<decltype(fieldVal)>;
+            if (wireType != wireTypeOf<Field>()) {
+              ok = fail("field has a different type in the data");
+            } else {
+              ok = readValue(fieldVal);
+            }
+          }
+        });
+        if (!(found ? ok : skipValue(wireType))) { // Unknown fields are skipped
+          return false;
+        }
+        popPath(pathSize);
+      }
+      return p == bytesEnd || fail("unexpected end of data");
+    } else {
+      static_assert(!std::is_same_v<T, T>, "type can't be read from binary");
+    }
+  }
+};
+
+// Reads `val` from binary data written by `toBinary`, returning an error like
+// "$.enemies[2].speed: unexpected end of data", or an empty string on success.
+// Data written with a different schema is rejected unless `allowSchemaChanges`
+// is set, in which case fields are matched by id as described above.
+template<typename T>
+String fromBinary(T &val, const Slice<std::uint8_t> &data, bool allowSchemaChanges = false) {
+  static_assert(HasSchemaHash<T>, "binary data must have a struct at the root");
+  BinaryReader reader { {}, data.data, data.data + len(data) };
+  std::uint64_t hash = 0;
+  if (reader.readFixed(hash, 8)) {
+    if (hash != SchemaHash<T>::value && !allowSchemaChanges) {
+      char message[96];
+      std::snprintf(message, sizeof(message), "schema hash %016llx doesn't match %016llx",
+          (unsigned long long)hash, (unsigned long long)SchemaHash<T>::value);
+      reader.fail(message);
+    } else if (reader.readValue(val) && reader.p != reader.end) {
+      reader.fail("unexpected data after value");
+    }
+  }
+  return reader.error;
+}
+
+template<typename T>
+String fromBinary(T *val, const Slice<std::uint8_t> &data, bool allowSchemaChanges = false) {
+  return fromBinary(deref(val), data, allowSchemaChanges);
+}
+
+
+//
+// Layout
+//
+
+// Generated as `Layout<T>::layout` for structs marked `//gx:layout`. Kept
+// across code reloads, it lets `migrate` move state into changed structs.
+
+struct FieldLayout {
+  const char *name;
+  int offset;
+  int size;
+  const char *type; // Describes the field's type, equal for equally laid out types
+  const char *defaultValue; // From the `default:` tag, empty if none
+  void (*destroy)(void *field);
+};
+
+struct StructLayout {
+  const char *name;
+  int size;
+  const FieldLayout *fields;
+  int numFields;
+};
+
+template<typename T>
+struct Layout {};
+
+template<typename T>
+void destroyField(void *field) {
+  static_cast<T *>(field)->~T();
+}
+
+// Moves fields from `oldBytes`, laid out as described by `oldLayout`, into
+// `newValue`. Fields with the same name and type are moved bytewise and others
+// get their `default:` values. Takes ownership of the old fields, so the old
+// value must not be destroyed afterwards. Old fields that aren't moved are
+// destroyed through `oldLayout`, so call this before unloading the old code.
+template<typename T>
+void migrate(const StructLayout &oldLayout, void *oldBytes, T &newValue) {
+  const auto &newLayout = Layout<T>::layout;
+  newValue = T {};
+  auto oldBase = static_cast<unsigned char *>(oldBytes);
+  auto newBase = reinterpret_cast<unsigned char *>(&newValue);
+  for (auto i = 0; i < oldLayout.numFields; ++i) {
+    auto &oldField = oldLayout.fields[i];
+    const FieldLayout *newField = nullptr;
+    for (auto j = 0; j < newLayout.numFields && !newField; ++j) {
+      auto &field = newLayout.fields[j];
+      if (std::strcmp(field.name, oldField.name) == 0 && std::strcmp(field.type, oldField.type) == 0
+          && field.size == oldField.size) {
+        newField = &field;
+      }
+    }
+    if (newField) {
+      newField->destroy(newBase + newField->offset);
+      std::memcpy(newBase + newField->offset, oldBase + oldField.offset, oldField.size);
+    } else {
+      oldField.destroy(oldBase + oldField.offset);
+    }
+  }
+}
+
+
+//
+// Inspect
+//
+
+// Walks a value for an immediate-mode property editor, so any struct with
+// `forEachField` metadata gets a panel for free. The visitor is implemented once
+// per UI library:
+//
+//   bool beginStruct(const char *label) -- whether to visit fields, eg. if open
+//   void endStruct()
+//   bool beginList(const char *label, int size, bool resizable) -- likewise
+//   ListEdit editItem(int index, int size) -- after each element's value
+//   ListEdit endList() -- `ListEdit::Add` to append an element
+//   bool boolean(const char *label, bool &val)
+//   bool number(const char *label, auto &val, double min, double max)
+//   bool string(const char *label, String &val)
+//   void enumeration(const char *label, const char *current, auto &&forEachOption)
+//
+// Value methods return whether the user changed the value. Numbers get their
+// range from `min` and `max` field attribs if the attribs type has them and they
+// differ, else the range of their type. Enums list their constants with
+// `forEachOption(func)`, which calls `func(name, selected)` for each and selects
+// the one it returns true for. List edits are applied after visiting the
+// elements, and added elements get their `default:` values.
+
+enum class ListEdit {
+  None,
+  Add,
+  Remove,
+  MoveUp,
+  MoveDown,
+};
+
+template<typename Visitor>
+struct Inspector {
+  Visitor &visitor;
+
+  template<typename T>
+  bool inspectValue(const char *label, T &val, double min, double max) {
+    if constexpr (std::is_same_v<T, bool>) {
+      return visitor.boolean(label, val);
+    } else if constexpr (IsEnum<T>) {
+      auto original = val;
+      auto changed = false;
+      String current = nameOf(val);
+      visitor.enumeration(label, (const char *)current, [&](auto &&func) {
+        forEachEnumValue(original, [&](const char *name, T value) {
+          if (func(name, value == original) && !(value == original)) {
+            val = value;
+            changed = true;
+          }
+        });
+      });
+      return changed;
+    } else if constexpr (std::is_arithmetic_v<T>) {
+      if (!(min < max)) {
+        min = double(std::numeric_limits<T>::lowest());
+        max = double(std::numeric_limits<T>::max());
+      }
+      return visitor.number(label, val, min, max);
+    } else if constexpr (std::is_same_v<T, String>) {
+      return visitor.string(label, val);
+    } else if constexpr (IsSlice<T>::value || IsArray<T>::value) {
+      constexpr auto resizable = IsSlice<T>::value;
+      if (!visitor.beginList(label, len(val), resizable)) {
+        return false;
+      }
+      auto changed = false;
+      auto edit = ListEdit::None;
+      auto editIndex = 0;
+      for (auto i = 0; auto &elem : val) {
+        char elemLabel[16];
+        std::snprintf(elemLabel, sizeof(elemLabel), "[%d]", i);
+        changed = inspectValue(elemLabel, elem, min, max) || changed; // Ranges apply to elements
+        if (auto itemEdit = visitor.editItem(i, len(val)); itemEdit != ListEdit::None) {
+          edit = itemEdit;
+          editIndex = i;
+        }
+        ++i;
+      }
+      auto listEdit = visitor.endList();
+      switch (edit) {
+      case ListEdit::Remove:
+        if constexpr (resizable) {
+          remove(val, editIndex);
+          changed = true;
+        }
+        break;
+      case ListEdit::MoveUp:
+        if (editIndex > 0) {
+          std::swap(val[editIndex - 1], val[editIndex]);
+          changed = true;
+        }
+        break;
+      case ListEdit::MoveDown:
+        if (editIndex + 1 < len(val)) {
+          std::swap(val[editIndex], val[editIndex + 1]);
+          changed = true;
+        }
+        break;
+      default:
+        break;
+      }
+      if constexpr (resizable) {
+        if (listEdit == ListEdit::Add) {
+          append(val);
+          changed = true;
+        }
+      }
+      return changed;
+    } else if constexpr (HasFields<T>) {
+      if (!visitor.beginStruct(label)) {
+        return false;
+      }
+      auto changed = false;
+      forEachField(val, [&](auto fieldTag, auto &fieldVal) {
+        double fieldMin = 0, fieldMax = 0;
+        if constexpr (requires { fieldTag.attribs.min + fieldTag.attribs.max; }) {
+          fieldMin = double(fieldTag.attribs.min);
+          fieldMax = double(fieldTag.attribs.max);
+        }
+        changed = inspectValue(fieldTag.attribs.name, fieldVal, fieldMin, fieldMax) || changed;
+      });
+      visitor.endStruct();
+      return changed;
+    } else {
+      static_assert(!std::is_same_v<T, T>, "type can't be inspected");
+    }
+  }
+};
+
+// Visits `val` with `visitor`, returning whether the user changed it. The root
+// value's label is empty.
+template<typename T, typename Visitor>
+bool inspect(T &val, Visitor &visitor) {
+  Inspector<Visitor> inspector { visitor };
+  return inspector.inspectValue("", val, 0, 0);
+}
+
+template<typename T, typename Visitor>
+bool inspect(T *val, Visitor &visitor) {
+  return inspect(deref(val), visitor);
+}
+
+
+//
+// Shader uniforms
+//
+
+struct UniformInfo {
+  const char *name; // As seen by `glGetUniformLocation`
+  const char *type; // GLSL type
+};
+
+
+//
+// GXSL built-ins
+//
+
+// CPU implementations of GLSL built-ins, so GXSL shaders compiled to C++ can
+// run headlessly. The `gxsl` Go package maps its types and functions to these.
+
+struct vec2 {
+  float x = 0, y = 0;
+
+  bool operator==(const vec2 &) const = default;
+};
+
+struct vec3 {
+  float x = 0, y = 0, z = 0;
+
+  bool operator==(const vec3 &) const = default;
+};
+
+struct vec4 {
+  float x = 0, y = 0, z = 0, w = 0;
+
+  bool operator==(const vec4 &) const = default;
+};
+
+template<typename V>
+concept Vec = std::is_same_v<V, vec2> || std::is_same_v<V, vec3> || std::is_same_v<V, vec4>;
+
+template<typename T>
+concept GenType = std::is_same_v<T, float> || Vec<T>;
+
+template<GenType T, typename F, typename... Ts>
+constexpr T mapComponents(F &&f, T a, const Ts &...bs) {
+  if constexpr (std::is_same_v<T, float>) {
+    return f(a, bs...);
+  } else {
+    a.x = f(a.x, bs.x...);
+    a.y = f(a.y, bs.y...);
+    if constexpr (requires { a.z; }) {
+      a.z = f(a.z, bs.z...);
+    }
+    if constexpr (requires { a.w; }) {
+      a.w = f(a.w, bs.w...);
+    }
+    return a;
+  }
+}
+
+template<GenType T>
+constexpr T splat(float f) {
+  return mapComponents(
+      [&](float) {
+        return f;
+      },
+      T());
+}
+
+template<Vec V>
+constexpr float component(const V &v, int i) {
+  if (i == 0) {
+    return v.x;
+  }
+  if (i == 1) {
+    return v.y;
+  }
+  if constexpr (requires { v.z; }) {
+    if (i == 2) {
+      return v.z;
+    }
+  }
+  if constexpr (requires { v.w; }) {
+    if (i == 3) {
+      return v.w;
+    }
+  }
+  return 0;
+}
+
+template<int... Is, Vec V>
+constexpr auto swizzle(const V &v) {
+  if constexpr (sizeof...(Is) == 2) {
+    return vec2 { component(v, Is)... };
+  } else if constexpr (sizeof...(Is) == 3) {
+    return vec3 { component(v, Is)... };
+  } else {
+    return vec4 { component(v, Is)... };
+  }
+}
+
+constexpr vec3 extend(const vec2 &v, float z) {
+  return { v.x, v.y, z };
+}
+
+constexpr vec4 extend(const vec3 &v, float w) {
+  return { v.x, v.y, v.z, w };
+}
+
+template<Vec V>
+constexpr V operator+(const V &a, const V &b) {
+  return mapComponents(std::plus(), a, b);
+}
+
+template<Vec V>
+constexpr V operator-(const V &a, const V &b) {
+  return mapComponents(std::minus(), a, b);
+}
+
+template<Vec V>
+constexpr V operator*(const V &a, const V &b) {
+  return mapComponents(std::multiplies(), a, b);
+}
+
+template<Vec V>
+constexpr V operator/(const V &a, const V &b) {
+  return mapComponents(std::divides(), a, b);
+}
+
+template<Vec V>
+constexpr V operator-(const V &a) {
+  return V() - a;
+}
+
+template<Vec V>
+constexpr V operator*(const V &a, float f) {
+  return a * splat<V>(f);
+}
+
+template<Vec V>
+constexpr V operator/(const V &a, float f) {
+  return a / splat<V>(f);
+}
+
+// Column-major like GLSL
+template<Vec V>
+struct Mat {
+  V cols[sizeof(V) / sizeof(float)];
+
+  bool operator==(const Mat &) const = default;
+};
+
+using mat2 = Mat<vec2>;
+using mat3 = Mat<vec3>;
+using mat4 = Mat<vec4>;
+
+template<Vec V, std::same_as<V>... Vs>
+constexpr Mat<V> columns(const V &col, const Vs &...cols) {
+  static_assert(1 + sizeof...(Vs) == sizeof(V) / sizeof(float), "gx: wrong number of matrix columns");
+  return { { col, cols... } };
+}
+
+template<Vec V>
+constexpr V operator*(const Mat<V> &m, const V &v) {
+  V result;
+  for (auto i = 0; auto &col : m.cols) {
+    result = result + col * component(v, i++);
+  }
+  return result;
+}
+
+template<Vec V>
+constexpr Mat<V> operator*(const Mat<V> &a, const Mat<V> &b) {
+  Mat<V> result;
+  for (auto i = 0; auto &col : b.cols) {
+    result.cols[i++] = a * col;
+  }
+  return result;
+}
+
+// Angle and trigonometry functions
+
+template<GenType T>
+constexpr T radians(const T &x) {
+  return mapComponents(
+      [](float x) {
+        return x * std::numbers::pi_v<float> / 180;
+      },
+      x);
+}
+
+template<GenType T>
+constexpr T degrees(const T &x) {
+  return mapComponents(
+      [](float x) {
+        return x * 180 / std::numbers::pi_v<float>;
+      },
+      x);
+}
+
+#define GX_GXSL_UNARY(name, expr)                                                                  \
+  template<GenType T>                                                                              \
+  inline T name(const T &x) {                                                                      \
+    return mapComponents(                                                                          \
+        [](float x) {                                                                              \
+          return expr;                                                                             \
+        },                                                                                         \
+        x);                                                                                        \
+  }
+
+GX_GXSL_UNARY(sin, std::sin(x))
+GX_GXSL_UNARY(cos, std::cos(x))
+GX_GXSL_UNARY(tan, std::tan(x))
+GX_GXSL_UNARY(asin, std::asin(x))
+GX_GXSL_UNARY(acos, std::acos(x))
+GX_GXSL_UNARY(atan, std::atan(x))
+GX_GXSL_UNARY(sinh, std::sinh(x))
+GX_GXSL_UNARY(cosh, std::cosh(x))
+GX_GXSL_UNARY(tanh, std::tanh(x))
+GX_GXSL_UNARY(asinh, std::asinh(x))
+GX_GXSL_UNARY(acosh, std::acosh(x))
+GX_GXSL_UNARY(atanh, std::atanh(x))
+
+// Exponential functions
+
+GX_GXSL_UNARY(exp, std::exp(x))
+GX_GXSL_UNARY(log, std::log(x))
+GX_GXSL_UNARY(exp2, std::exp2(x))
+GX_GXSL_UNARY(log2, std::log2(x))
+GX_GXSL_UNARY(sqrt, std::sqrt(x))
+GX_GXSL_UNARY(inversesqrt, 1 / std::sqrt(x))
+
+// Common functions
+
+GX_GXSL_UNARY(abs, std::abs(x))
+GX_GXSL_UNARY(sign, float((x > 0) - (x < 0)))
+GX_GXSL_UNARY(floor, std::floor(x))
+GX_GXSL_UNARY(ceil, std::ceil(x))
+GX_GXSL_UNARY(trunc, std::trunc(x))
+GX_GXSL_UNARY(round, std::round(x))
+GX_GXSL_UNARY(roundEven, std::nearbyint(x)) // The default rounding mode rounds halves to even
+GX_GXSL_UNARY(fract, x - std::floor(x))
+
+#undef GX_GXSL_UNARY
+
+template<GenType T>
+inline T mod(const T &x, const T &y) {
+  return mapComponents(
+      [](float x, float y) {
+        return x - y * std::floor(x / y);
+      },
+      x, y);
+}
+
+template<Vec V>
+inline V mod(const V &x, float y) {
+  return mod(x, splat<V>(y));
+}
+
+template<GenType T>
+inline T atan(const T &y, const T &x) {
+  return mapComponents(
+      [](float y, float x) {
+        return std::atan2(y, x);
+      },
+      y, x);
+}
+
+template<GenType T>
+inline T pow(const T &x, const T &y) {
+  return mapComponents(
+      [](float x, float y) {
+        return std::pow(x, y);
+      },
+      x, y);
+}
+
+template<GenType T>
+constexpr T min(const T &x, const T &y) {
+  return mapComponents(
+      [](float x, float y) {
+        return y < x ? y : x;
+      },
+      x, y);
+}
+
+template<Vec V>
+constexpr V min(const V &x, float y) {
+  return min(x, splat<V>(y));
+}
+
+template<GenType T>
+constexpr T max(const T &x, const T &y) {
+  return mapComponents(
+      [](float x, float y) {
+        return x < y ? y : x;
+      },
+      x, y);
+}
+
+template<Vec V>
+constexpr V max(const V &x, float y) {
+  return max(x, splat<V>(y));
+}
+
+template<GenType T>
+constexpr T clamp(const T &x, const T &minVal, const T &maxVal) {
+  return min(max(x, minVal), maxVal);
+}
+
+template<Vec V>
+constexpr V clamp(const V &x, float minVal, float maxVal) {
+  return clamp(x, splat<V>(minVal), splat<V>(maxVal));
+}
+
+template<GenType T>
+constexpr T mix(const T &x, const T &y, float a) {
+  return x * (1 - a) + y * a;
+}
+
+template<Vec V>
+constexpr V mix(const V &x, const V &y, const V &a) {
+  return x * (splat<V>(1) - a) + y * a;
+}
+
+template<GenType T>
+constexpr T step(const T &edge, const T &x) {
+  return mapComponents(
+      [](float edge, float x) {
+        return x < edge ? 0.0f : 1.0f;
+      },
+      edge, x);
+}
+
+template<Vec V>
+constexpr V step(float edge, const V &x) {
+  return step(splat<V>(edge), x);
+}
+
+template<GenType T>
+constexpr T smoothstep(const T &edge0, const T &edge1, const T &x) {
+  return mapComponents(
+      [](float edge0, float edge1, float x) {
+        auto t = clamp((x - edge0) / (edge1 - edge0), 0.0f, 1.0f);
+        return t * t * (3 - 2 * t);
+      },
+      edge0, edge1, x);
+}
+
+template<Vec V>
+constexpr V smoothstep(float edge0, float edge1, const V &x) {
+  return smoothstep(splat<V>(edge0), splat<V>(edge1), x);
+}
+
+// Geometric functions
+
+template<GenType T>
+constexpr float dot(const T &x, const T &y) {
+  auto result = 0.0f;
+  mapComponents(
+      [&](float x, float y) {
+        result += x * y;
+        return x;
+      },
+      x, y);
+  return result;
+}
+
+template<GenType T>
+inline float length(const T &x) {
+  return std::sqrt(dot(x, x));
+}
+
+template<GenType T>
+inline float distance(const T &p0, const T &p1) {
+  return length(p0 - p1);
+}
+
+template<GenType T>
+inline T normalize(const T &x) {
+  return x / length(x);
+}
+
+constexpr vec3 cross(const vec3 &x, const vec3 &y) {
+  return { x.y * y.z - y.y * x.z, x.z * y.x - y.z * x.x, x.x * y.y - y.x * x.y };
+}
+
+template<GenType T>
+constexpr T faceforward(const T &n, const T &i, const T &nRef) {
+  return dot(nRef, i) < 0 ? n : -n;
+}
+
+template<GenType T>
+constexpr T reflect(const T &i, const T &n) {
+  return i - n * (2 * dot(n, i));
+}
+
+template<GenType T>
+inline T refract(const T &i, const T &n, float eta) {
+  auto d = dot(n, i);
+  auto k = 1 - eta * eta * (1 - d * d);
+  if (k < 0) {
+    return T();
+  }
+  return i * eta - n * (eta * d + std::sqrt(k));
+}
+
+// Matrix functions
+
+template<typename M>
+concept MatType = std::is_same_v<M, mat2> || std::is_same_v<M, mat3> || std::is_same_v<M, mat4>;
+
+template<MatType M>
+constexpr M matrixCompMult(M x, const M &y) {
+  for (auto i = 0; auto &col : x.cols) {
+    col = col * y.cols[i++];
+  }
+  return x;
+}
+
+template<MatType M>
+constexpr M transpose(const M &m) {
+  M result;
+  for (auto i = 0; auto &col : result.cols) {
+    auto j = 0;
+    col = mapComponents(
+        [&](float) {
+          return component(m.cols[j++], i);
+        },
+        col);
+    ++i;
+  }
+  return result;
+}
+
+// The 4x4 cases follow Eric Lengyel's 'Foundations of Game Engine Development',
+// treating each column as a 3D vector plus a fourth component
+
+template<MatType M>
+constexpr float determinant(const M &m) {
+  if constexpr (std::is_same_v<M, mat2>) {
+    return m.cols[0].x * m.cols[1].y - m.cols[1].x * m.cols[0].y;
+  } else if constexpr (std::is_same_v<M, mat3>) {
+    return dot(m.cols[0], cross(m.cols[1], m.cols[2]));
+  } else {
+    auto a = swizzle<0, 1, 2>(m.cols[0]), b = swizzle<0, 1, 2>(m.cols[1]);
+    auto c = swizzle<0, 1, 2>(m.cols[2]), d = swizzle<0, 1, 2>(m.cols[3]);
+    auto x = m.cols[0].w, y = m.cols[1].w, z = m.cols[2].w, w = m.cols[3].w;
+    return dot(cross(a, b), c * w - d * z) + dot(cross(c, d), a * y - b * x);
+  }
+}
+
+template<MatType M>
+constexpr M inverse(const M &m) {
+  auto invDet = 1 / determinant(m);
+  if constexpr (std::is_same_v<M, mat2>) {
+    return columns(vec2 { m.cols[1].y, -m.cols[0].y } * invDet,
+        vec2 { -m.cols[1].x, m.cols[0].x } * invDet);
+  } else if constexpr (std::is_same_v<M, mat3>) {
+    auto &a = m.cols[0], &b = m.cols[1], &c = m.cols[2];
+    return transpose(columns(cross(b, c) * invDet, cross(c, a) * invDet, cross(a, b) * invDet));
+  } else {
+    auto a = swizzle<0, 1, 2>(m.cols[0]), b = swizzle<0, 1, 2>(m.cols[1]);
+    auto c = swizzle<0, 1, 2>(m.cols[2]), d = swizzle<0, 1, 2>(m.cols[3]);
+    auto x = m.cols[0].w, y = m.cols[1].w, z = m.cols[2].w, w = m.cols[3].w;
+    auto s = cross(a, b) * invDet, t = cross(c, d) * invDet;
+    auto u = (a * y - b * x) * invDet, v = (c * w - d * z) * invDet;
+    return transpose(columns(extend(cross(b, v) + t * y, -dot(b, t)),
+        extend(cross(v, a) - t * x, dot(a, t)), extend(cross(d, u) + s * w, -dot(d, s)),
+        extend(cross(u, c) - s * z, dot(c, s))));
+  }
+}
+
+// Derivatives, which are zero on the CPU since there are no neighboring
+// fragments to difference with
+
+template<GenType T>
+constexpr T dFdx(const T &) {
+  return T();
+}
+
+template<GenType T>
+constexpr T dFdy(const T &) {
+  return T();
+}
+
+template<GenType T>
+constexpr T fwidth(const T &) {
+  return T();
+}
+
+// Textures
+
+// A CPU image sampled with nearest filtering and clamp-to-edge wrapping.
+// Empty samplers read as opaque white.
+struct sampler2D {
+  int width = 0;
+  int height = 0;
+  Slice<vec4> pixels;
+};
+
+// Index of the texel at a texture coordinate, clamped to the edge
+inline int texelIndex(float coord, int size) {
+  auto i = int(std::floor(coord * float(size)));
+  return i < 0 ? 0 : i >= size ? size - 1 : i;
+}
+
+inline vec4 texture2D(const sampler2D &sampler, vec2 coord) {
+  if (sampler.width <= 0 || sampler.height <= 0) {
+    return { 1, 1, 1, 1 };
+  }
+  auto x = texelIndex(coord.x, sampler.width), y = texelIndex(coord.y, sampler.height);
+  return sampler.pixels[y * sampler.width + x];
+}
+
+inline vec4 texture2DProj(const sampler2D &sampler, vec3 coord) {
+  return texture2D(sampler, { coord.x / coord.z, coord.y / coord.z });
+}
+
+// CPU samplers have no mipmaps, so levels of detail are ignored
+inline vec4 texture2DLod(const sampler2D &sampler, vec2 coord, float) {
+  return texture2D(sampler, coord);
+}
+
+// Six `size` by `size` faces in the order +X, -X, +Y, -Y, +Z, -Z, sampled like
+// `sampler2D`
+struct samplerCube {
+  int size = 0;
+  Slice<vec4> pixels;
+};
+
+inline vec4 textureCube(const samplerCube &sampler, vec3 dir) {
+  if (sampler.size <= 0) {
+    return { 1, 1, 1, 1 };
+  }
+  // Select the face by the major axis and project onto it as in the GL spec
+  auto ax = std::abs(dir.x), ay = std::abs(dir.y), az = std::abs(dir.z);
+  int face;
+  float sc, tc, ma;
+  if (ax >= ay && ax >= az) {
+    face = dir.x > 0 ? 0 : 1;
+    sc = dir.x > 0 ? -dir.z : dir.z;
+    tc = -dir.y;
+    ma = ax;
+  } else if (ay >= az) {
+    face = dir.y > 0 ? 2 : 3;
+    sc = dir.x;
+    tc = dir.y > 0 ? dir.z : -dir.z;
+    ma = ay;
+  } else {
+    face = dir.z > 0 ? 4 : 5;
+    sc = dir.z > 0 ? dir.x : -dir.x;
+    tc = -dir.y;
+    ma = az;
+  }
+  auto x = texelIndex((sc / ma + 1) / 2, sampler.size);
+  auto y = texelIndex((tc / ma + 1) / 2, sampler.size);
+  return sampler.pixels[(face * sampler.size + y) * sampler.size + x];
+}
+
+inline vec4 textureCubeLod(const samplerCube &sampler, vec3 dir, float) {
+  return textureCube(sampler, dir);
+}
+
+// Stage outputs, read back after calling a shader
+
+inline vec4 gl_Position;
+inline vec4 gl_FragColor;
+
+// Compute
+
+struct uvec3 {
+  uint x = 0, y = 0, z = 0;
+
+  bool operator==(const uvec3 &) const = default;
+};
+
+// Set before calling a compute shader for each invocation
+inline uvec3 gl_GlobalInvocationID;
+inline uvec3 gl_LocalInvocationID;
+inline uvec3 gl_WorkGroupID;
+inline uvec3 gl_NumWorkGroups;
+inline uint gl_LocalInvocationIndex;
+
+// Invocations run one at a time on the CPU, so barriers have nothing to wait for
+inline void barrier() {
+}
+inline void memoryBarrierBuffer() {
+}
 
 }
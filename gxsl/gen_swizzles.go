@@ -0,0 +1,58 @@
+//go:build ignore
+
+// Generates 'swizzles.gx.go', with a method for every swizzle of each vector
+// type's components, including repeated components as GLSL allows
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+)
+
+var vecTypes = []struct {
+	name       string
+	components string
+}{
+	{"Vec2", "xy"},
+	{"Vec3", "xyz"},
+	{"Vec4", "xyzw"},
+}
+
+var resultTypes = map[int]string{2: "Vec2", 3: "Vec3", 4: "Vec4"}
+
+func main() {
+	builder := &strings.Builder{}
+	builder.WriteString("// Code generated by gen_swizzles.go. DO NOT EDIT.\n\n")
+	builder.WriteString("package gxsl\n")
+	for _, vecType := range vecTypes {
+		for length := 2; length <= 4; length++ {
+			// Count through the swizzles of this length in base `len(components)`
+			nComponents := len(vecType.components)
+			nSwizzles := 1
+			for i := 0; i < length; i++ {
+				nSwizzles *= nComponents
+			}
+			for n := 0; n < nSwizzles; n++ {
+				glsl, indices := make([]byte, length), make([]string, length)
+				for i, rest := length-1, n; i >= 0; i, rest = i-1, rest/nComponents {
+					glsl[i] = vecType.components[rest%nComponents]
+					indices[i] = fmt.Sprint(rest % nComponents)
+				}
+				fmt.Fprintf(builder, "\n//gxsl:extern .%s\n", glsl)
+				fmt.Fprintf(builder, "//gx:extern gx::swizzle<%s>\n", strings.Join(indices, ", "))
+				fmt.Fprintf(builder, "func (v %s) %s() %s\n", vecType.name, strings.ToUpper(string(glsl)), resultTypes[length])
+			}
+		}
+	}
+	source, err := format.Source([]byte(builder.String()))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile("swizzles.gx.go", source, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,589 @@
+// Package gxsl declares the GLSL built-in types, operators and functions for
+// GXSL shaders. Each maps to GLSL with `//gxsl:extern`, to WGSL with
+// `//wgsl:extern` where the name differs, and to the CPU implementations in
+// 'gx.hh' with `//gx:extern`. Where WGSL has no equivalent, the compiler writes
+// `gx_` helper functions into the WGSL output instead. Built-ins that GLSL ES
+// 1.00 lacks, like `Transpose` and `DFdx`, need `//gxsl:profile 300es` or later.
+//
+// Built-ins involving boolean or integer vectors, `out` parameters (`modf`) or
+// non-square matrices (`outerProduct`) are left out, since GXSL has no such
+// types.
+package gxsl
+
+//
+// Vectors
+//
+
+//gxsl:extern vec2
+//wgsl:extern vec2<f32>
+//gx:extern gx::vec2
+type Vec2 struct {
+	X, Y float64
+}
+
+//gxsl:extern vec3
+//wgsl:extern vec3<f32>
+//gx:extern gx::vec3
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+//gxsl:extern vec4
+//wgsl:extern vec4<f32>
+//gx:extern gx::vec4
+type Vec4 struct {
+	X, Y, Z, W float64
+}
+
+//gxsl:extern vec3
+//wgsl:extern vec3<f32>
+//gx:extern gx::extend
+func Vec3FromVec2(v Vec2, z float64) Vec3
+
+//gxsl:extern vec4
+//wgsl:extern vec4<f32>
+//gx:extern gx::extend
+func Vec4FromVec3(v Vec3, w float64) Vec4
+
+//gxsl:extern +
+//gx:extern gx::operator+
+func (v Vec2) Add(u Vec2) Vec2
+
+//gxsl:extern -
+//gx:extern gx::operator-
+func (v Vec2) Sub(u Vec2) Vec2
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (v Vec2) Mul(u Vec2) Vec2
+
+//gxsl:extern /
+//gx:extern gx::operator/
+func (v Vec2) Div(u Vec2) Vec2
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (v Vec2) Scale(f float64) Vec2
+
+//gxsl:extern -
+//gx:extern gx::operator-
+func (v Vec2) Negate() Vec2
+
+//gxsl:extern +
+//gx:extern gx::operator+
+func (v Vec3) Add(u Vec3) Vec3
+
+//gxsl:extern -
+//gx:extern gx::operator-
+func (v Vec3) Sub(u Vec3) Vec3
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (v Vec3) Mul(u Vec3) Vec3
+
+//gxsl:extern /
+//gx:extern gx::operator/
+func (v Vec3) Div(u Vec3) Vec3
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (v Vec3) Scale(f float64) Vec3
+
+//gxsl:extern -
+//gx:extern gx::operator-
+func (v Vec3) Negate() Vec3
+
+//gxsl:extern +
+//gx:extern gx::operator+
+func (v Vec4) Add(u Vec4) Vec4
+
+//gxsl:extern -
+//gx:extern gx::operator-
+func (v Vec4) Sub(u Vec4) Vec4
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (v Vec4) Mul(u Vec4) Vec4
+
+//gxsl:extern /
+//gx:extern gx::operator/
+func (v Vec4) Div(u Vec4) Vec4
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (v Vec4) Scale(f float64) Vec4
+
+//gxsl:extern -
+//gx:extern gx::operator-
+func (v Vec4) Negate() Vec4
+
+//
+// Swizzles
+//
+
+// Methods like `v.ZYX()` for every swizzle of two to four components,
+// repeats included, are generated in 'swizzles.gx.go'
+//
+//go:generate go run gen_swizzles.go
+
+//
+// Matrices
+//
+
+// Column-major, like GLSL
+//
+//gxsl:extern mat2
+//wgsl:extern mat2x2<f32>
+//gx:extern gx::mat2
+type Mat2 struct {
+	cols [2]Vec2
+}
+
+//gxsl:extern mat2
+//wgsl:extern mat2x2<f32>
+//gx:extern gx::columns
+func NewMat2(c0, c1 Vec2) Mat2
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (m Mat2) Mul(n Mat2) Mat2
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (m Mat2) Transform(v Vec2) Vec2
+
+// Column-major, like GLSL
+//
+//gxsl:extern mat3
+//wgsl:extern mat3x3<f32>
+//gx:extern gx::mat3
+type Mat3 struct {
+	cols [3]Vec3
+}
+
+//gxsl:extern mat3
+//wgsl:extern mat3x3<f32>
+//gx:extern gx::columns
+func NewMat3(c0, c1, c2 Vec3) Mat3
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (m Mat3) Mul(n Mat3) Mat3
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (m Mat3) Transform(v Vec3) Vec3
+
+// Column-major, like GLSL
+//
+//gxsl:extern mat4
+//wgsl:extern mat4x4<f32>
+//gx:extern gx::mat4
+type Mat4 struct {
+	cols [4]Vec4
+}
+
+//gxsl:extern mat4
+//wgsl:extern mat4x4<f32>
+//gx:extern gx::columns
+func NewMat4(c0, c1, c2, c3 Vec4) Mat4
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (m Mat4) Mul(n Mat4) Mat4
+
+//gxsl:extern *
+//gx:extern gx::operator*
+func (m Mat4) Transform(v Vec4) Vec4
+
+//
+// Functions
+//
+
+type GenType interface {
+	float64 | Vec2 | Vec3 | Vec4
+}
+
+type MatType interface {
+	Mat2 | Mat3 | Mat4
+}
+
+// Angle and trigonometry
+
+//gxsl:extern radians
+//gx:extern gx::radians
+func Radians[T GenType](degrees T) T
+
+//gxsl:extern degrees
+//gx:extern gx::degrees
+func Degrees[T GenType](radians T) T
+
+//gxsl:extern sin
+//gx:extern gx::sin
+func Sin[T GenType](angle T) T
+
+//gxsl:extern cos
+//gx:extern gx::cos
+func Cos[T GenType](angle T) T
+
+//gxsl:extern tan
+//gx:extern gx::tan
+func Tan[T GenType](angle T) T
+
+//gxsl:extern asin
+//gx:extern gx::asin
+func Asin[T GenType](x T) T
+
+//gxsl:extern acos
+//gx:extern gx::acos
+func Acos[T GenType](x T) T
+
+//gxsl:extern atan
+//gx:extern gx::atan
+func Atan[T GenType](yOverX T) T
+
+//gxsl:extern atan
+//wgsl:extern atan2
+//gx:extern gx::atan
+func Atan2[T GenType](y, x T) T
+
+//gxsl:extern sinh
+//gx:extern gx::sinh
+func Sinh[T GenType](x T) T
+
+//gxsl:extern cosh
+//gx:extern gx::cosh
+func Cosh[T GenType](x T) T
+
+//gxsl:extern tanh
+//gx:extern gx::tanh
+func Tanh[T GenType](x T) T
+
+//gxsl:extern asinh
+//gx:extern gx::asinh
+func Asinh[T GenType](x T) T
+
+//gxsl:extern acosh
+//gx:extern gx::acosh
+func Acosh[T GenType](x T) T
+
+//gxsl:extern atanh
+//gx:extern gx::atanh
+func Atanh[T GenType](x T) T
+
+// Exponential
+
+//gxsl:extern pow
+//gx:extern gx::pow
+func Pow[T GenType](x, y T) T
+
+//gxsl:extern exp
+//gx:extern gx::exp
+func Exp[T GenType](x T) T
+
+//gxsl:extern log
+//gx:extern gx::log
+func Log[T GenType](x T) T
+
+//gxsl:extern exp2
+//gx:extern gx::exp2
+func Exp2[T GenType](x T) T
+
+//gxsl:extern log2
+//gx:extern gx::log2
+func Log2[T GenType](x T) T
+
+//gxsl:extern sqrt
+//gx:extern gx::sqrt
+func Sqrt[T GenType](x T) T
+
+//gxsl:extern inversesqrt
+//wgsl:extern inverseSqrt
+//gx:extern gx::inversesqrt
+func InverseSqrt[T GenType](x T) T
+
+// Common
+
+//gxsl:extern abs
+//gx:extern gx::abs
+func Abs[T GenType](x T) T
+
+//gxsl:extern sign
+//gx:extern gx::sign
+func Sign[T GenType](x T) T
+
+//gxsl:extern floor
+//gx:extern gx::floor
+func Floor[T GenType](x T) T
+
+//gxsl:extern ceil
+//gx:extern gx::ceil
+func Ceil[T GenType](x T) T
+
+//gxsl:extern trunc
+//gx:extern gx::trunc
+func Trunc[T GenType](x T) T
+
+//gxsl:extern round
+//gx:extern gx::round
+func Round[T GenType](x T) T
+
+//gxsl:extern roundEven
+//wgsl:extern round
+//gx:extern gx::roundEven
+func RoundEven[T GenType](x T) T
+
+//gxsl:extern fract
+//gx:extern gx::fract
+func Fract[T GenType](x T) T
+
+//gxsl:extern mod
+//wgsl:extern gx_mod
+//gx:extern gx::mod
+func Mod[T GenType](x, y T) T
+
+//gxsl:extern mod
+//wgsl:extern gx_modScalar
+//gx:extern gx::mod
+func ModScalar[T GenType](x T, y float64) T
+
+//gxsl:extern min
+//gx:extern gx::min
+func Min[T GenType](x, y T) T
+
+//gxsl:extern min
+//wgsl:extern gx_minScalar
+//gx:extern gx::min
+func MinScalar[T GenType](x T, y float64) T
+
+//gxsl:extern max
+//gx:extern gx::max
+func Max[T GenType](x, y T) T
+
+//gxsl:extern max
+//wgsl:extern gx_maxScalar
+//gx:extern gx::max
+func MaxScalar[T GenType](x T, y float64) T
+
+//gxsl:extern clamp
+//gx:extern gx::clamp
+func Clamp[T GenType](x, minVal, maxVal T) T
+
+//gxsl:extern clamp
+//wgsl:extern gx_clampScalar
+//gx:extern gx::clamp
+func ClampScalar[T GenType](x T, minVal, maxVal float64) T
+
+//gxsl:extern mix
+//gx:extern gx::mix
+func Mix[T GenType](x, y T, a float64) T
+
+//gxsl:extern mix
+//gx:extern gx::mix
+func MixEach[T GenType](x, y, a T) T
+
+//gxsl:extern step
+//gx:extern gx::step
+func Step[T GenType](edge, x T) T
+
+//gxsl:extern step
+//wgsl:extern gx_stepScalar
+//gx:extern gx::step
+func StepScalar[T GenType](edge float64, x T) T
+
+//gxsl:extern smoothstep
+//gx:extern gx::smoothstep
+func Smoothstep[T GenType](edge0, edge1, x T) T
+
+//gxsl:extern smoothstep
+//wgsl:extern gx_smoothstepScalar
+//gx:extern gx::smoothstep
+func SmoothstepScalar[T GenType](edge0, edge1 float64, x T) T
+
+// Geometric
+
+//gxsl:extern length
+//gx:extern gx::length
+func Length[T GenType](x T) float64
+
+//gxsl:extern distance
+//gx:extern gx::distance
+func Distance[T GenType](p0, p1 T) float64
+
+//gxsl:extern dot
+//gx:extern gx::dot
+func Dot[T GenType](x, y T) float64
+
+//gxsl:extern normalize
+//gx:extern gx::normalize
+func Normalize[T GenType](x T) T
+
+//gxsl:extern faceforward
+//wgsl:extern faceForward
+//gx:extern gx::faceforward
+func FaceForward[T GenType](n, i, nRef T) T
+
+//gxsl:extern reflect
+//gx:extern gx::reflect
+func Reflect[T GenType](i, n T) T
+
+//gxsl:extern refract
+//gx:extern gx::refract
+func Refract[T GenType](i, n T, eta float64) T
+
+//gxsl:extern cross
+//gx:extern gx::cross
+func Cross(x, y Vec3) Vec3
+
+// Matrix
+
+//gxsl:extern matrixCompMult
+//wgsl:extern gx_matrixCompMult
+//gx:extern gx::matrixCompMult
+func MatrixCompMult[M MatType](x, y M) M
+
+//gxsl:extern transpose
+//gx:extern gx::transpose
+func Transpose[M MatType](m M) M
+
+//gxsl:extern determinant
+//gx:extern gx::determinant
+func Determinant[M MatType](m M) float64
+
+//gxsl:extern inverse
+//wgsl:extern gx_inverse
+//gx:extern gx::inverse
+func Inverse[M MatType](m M) M
+
+// Derivatives, in fragment shaders only. On the CPU there are no neighboring
+// fragments to difference with, so these are zero.
+
+//gxsl:extern dFdx
+//wgsl:extern dpdx
+//gx:extern gx::dFdx
+func DFdx[T GenType](p T) T
+
+//gxsl:extern dFdy
+//wgsl:extern dpdy
+//gx:extern gx::dFdy
+func DFdy[T GenType](p T) T
+
+//gxsl:extern fwidth
+//gx:extern gx::fwidth
+func Fwidth[T GenType](p T) T
+
+//
+// Textures
+//
+
+// On the CPU, a `Width` by `Height` image sampled with nearest filtering
+//
+//gxsl:extern sampler2D
+//wgsl:extern texture_2d<f32>
+//gx:extern gx::sampler2D
+type Sampler2D struct {
+	Width, Height int
+	Pixels        []Vec4
+}
+
+//gxsl:extern texture2D
+//wgsl:extern textureSample
+//gx:extern gx::texture2D
+func Texture2D(sampler Sampler2D, coord Vec2) Vec4
+
+//gxsl:extern texture2DProj
+//wgsl:extern gx_texture2DProj
+//gx:extern gx::texture2DProj
+func Texture2DProj(sampler Sampler2D, coord Vec3) Vec4
+
+//gxsl:extern texture2DLod
+//wgsl:extern textureSampleLevel
+//gx:extern gx::texture2DLod
+func Texture2DLod(sampler Sampler2D, coord Vec2, lod float64) Vec4
+
+// On the CPU, six `Size` by `Size` faces in the order +X, -X, +Y, -Y, +Z, -Z,
+// sampled with nearest filtering
+//
+//gxsl:extern samplerCube
+//wgsl:extern texture_cube<f32>
+//gx:extern gx::samplerCube
+type SamplerCube struct {
+	Size   int
+	Pixels []Vec4
+}
+
+//gxsl:extern textureCube
+//wgsl:extern textureSample
+//gx:extern gx::textureCube
+func TextureCube(sampler SamplerCube, dir Vec3) Vec4
+
+//gxsl:extern textureCubeLod
+//wgsl:extern textureSampleLevel
+//gx:extern gx::textureCubeLod
+func TextureCubeLod(sampler SamplerCube, dir Vec3, lod float64) Vec4
+
+//
+// Stage outputs
+//
+
+//gxsl:extern gl_Position
+//gx:extern gx::gl_Position
+var Position Vec4
+
+//gxsl:extern gl_FragColor
+//gx:extern gx::gl_FragColor
+var FragColor Vec4
+
+//
+// Compute
+//
+
+// Unsigned vector of invocation and workgroup indices
+//
+//gxsl:extern uvec3
+//wgsl:extern vec3<u32>
+//gx:extern gx::uvec3
+type UVec3 struct {
+	X, Y, Z uint
+}
+
+// On the CPU, set these before calling a compute shader for each invocation
+
+//gxsl:extern gl_GlobalInvocationID
+//wgsl:extern gx_GlobalInvocationID
+//gx:extern gx::gl_GlobalInvocationID
+var GlobalInvocationID UVec3
+
+//gxsl:extern gl_LocalInvocationID
+//wgsl:extern gx_LocalInvocationID
+//gx:extern gx::gl_LocalInvocationID
+var LocalInvocationID UVec3
+
+//gxsl:extern gl_WorkGroupID
+//wgsl:extern gx_WorkGroupID
+//gx:extern gx::gl_WorkGroupID
+var WorkGroupID UVec3
+
+//gxsl:extern gl_NumWorkGroups
+//wgsl:extern gx_NumWorkGroups
+//gx:extern gx::gl_NumWorkGroups
+var NumWorkGroups UVec3
+
+//gxsl:extern gl_LocalInvocationIndex
+//wgsl:extern gx_LocalInvocationIndex
+//gx:extern gx::gl_LocalInvocationIndex
+var LocalInvocationIndex uint
+
+// Waits for all invocations in the workgroup to reach the barrier
+//
+//gxsl:extern barrier
+//wgsl:extern workgroupBarrier
+//gx:extern gx::barrier
+func Barrier()
+
+// Makes storage buffer writes visible to other invocations
+//
+//gxsl:extern memoryBarrierBuffer
+//wgsl:extern storageBarrier
+//gx:extern gx::memoryBarrierBuffer
+func StorageBarrier()
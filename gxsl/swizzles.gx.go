@@ -0,0 +1,1927 @@
+// Code generated by gen_swizzles.go. DO NOT EDIT.
+
+package gxsl
+
+//gxsl:extern .xx
+//gx:extern gx::swizzle<0, 0>
+func (v Vec2) XX() Vec2
+
+//gxsl:extern .xy
+//gx:extern gx::swizzle<0, 1>
+func (v Vec2) XY() Vec2
+
+//gxsl:extern .yx
+//gx:extern gx::swizzle<1, 0>
+func (v Vec2) YX() Vec2
+
+//gxsl:extern .yy
+//gx:extern gx::swizzle<1, 1>
+func (v Vec2) YY() Vec2
+
+//gxsl:extern .xxx
+//gx:extern gx::swizzle<0, 0, 0>
+func (v Vec2) XXX() Vec3
+
+//gxsl:extern .xxy
+//gx:extern gx::swizzle<0, 0, 1>
+func (v Vec2) XXY() Vec3
+
+//gxsl:extern .xyx
+//gx:extern gx::swizzle<0, 1, 0>
+func (v Vec2) XYX() Vec3
+
+//gxsl:extern .xyy
+//gx:extern gx::swizzle<0, 1, 1>
+func (v Vec2) XYY() Vec3
+
+//gxsl:extern .yxx
+//gx:extern gx::swizzle<1, 0, 0>
+func (v Vec2) YXX() Vec3
+
+//gxsl:extern .yxy
+//gx:extern gx::swizzle<1, 0, 1>
+func (v Vec2) YXY() Vec3
+
+//gxsl:extern .yyx
+//gx:extern gx::swizzle<1, 1, 0>
+func (v Vec2) YYX() Vec3
+
+//gxsl:extern .yyy
+//gx:extern gx::swizzle<1, 1, 1>
+func (v Vec2) YYY() Vec3
+
+//gxsl:extern .xxxx
+//gx:extern gx::swizzle<0, 0, 0, 0>
+func (v Vec2) XXXX() Vec4
+
+//gxsl:extern .xxxy
+//gx:extern gx::swizzle<0, 0, 0, 1>
+func (v Vec2) XXXY() Vec4
+
+//gxsl:extern .xxyx
+//gx:extern gx::swizzle<0, 0, 1, 0>
+func (v Vec2) XXYX() Vec4
+
+//gxsl:extern .xxyy
+//gx:extern gx::swizzle<0, 0, 1, 1>
+func (v Vec2) XXYY() Vec4
+
+//gxsl:extern .xyxx
+//gx:extern gx::swizzle<0, 1, 0, 0>
+func (v Vec2) XYXX() Vec4
+
+//gxsl:extern .xyxy
+//gx:extern gx::swizzle<0, 1, 0, 1>
+func (v Vec2) XYXY() Vec4
+
+//gxsl:extern .xyyx
+//gx:extern gx::swizzle<0, 1, 1, 0>
+func (v Vec2) XYYX() Vec4
+
+//gxsl:extern .xyyy
+//gx:extern gx::swizzle<0, 1, 1, 1>
+func (v Vec2) XYYY() Vec4
+
+//gxsl:extern .yxxx
+//gx:extern gx::swizzle<1, 0, 0, 0>
+func (v Vec2) YXXX() Vec4
+
+//gxsl:extern .yxxy
+//gx:extern gx::swizzle<1, 0, 0, 1>
+func (v Vec2) YXXY() Vec4
+
+//gxsl:extern .yxyx
+//gx:extern gx::swizzle<1, 0, 1, 0>
+func (v Vec2) YXYX() Vec4
+
+//gxsl:extern .yxyy
+//gx:extern gx::swizzle<1, 0, 1, 1>
+func (v Vec2) YXYY() Vec4
+
+//gxsl:extern .yyxx
+//gx:extern gx::swizzle<1, 1, 0, 0>
+func (v Vec2) YYXX() Vec4
+
+//gxsl:extern .yyxy
+//gx:extern gx::swizzle<1, 1, 0, 1>
+func (v Vec2) YYXY() Vec4
+
+//gxsl:extern .yyyx
+//gx:extern gx::swizzle<1, 1, 1, 0>
+func (v Vec2) YYYX() Vec4
+
+//gxsl:extern .yyyy
+//gx:extern gx::swizzle<1, 1, 1, 1>
+func (v Vec2) YYYY() Vec4
+
+//gxsl:extern .xx
+//gx:extern gx::swizzle<0, 0>
+func (v Vec3) XX() Vec2
+
+//gxsl:extern .xy
+//gx:extern gx::swizzle<0, 1>
+func (v Vec3) XY() Vec2
+
+//gxsl:extern .xz
+//gx:extern gx::swizzle<0, 2>
+func (v Vec3) XZ() Vec2
+
+//gxsl:extern .yx
+//gx:extern gx::swizzle<1, 0>
+func (v Vec3) YX() Vec2
+
+//gxsl:extern .yy
+//gx:extern gx::swizzle<1, 1>
+func (v Vec3) YY() Vec2
+
+//gxsl:extern .yz
+//gx:extern gx::swizzle<1, 2>
+func (v Vec3) YZ() Vec2
+
+//gxsl:extern .zx
+//gx:extern gx::swizzle<2, 0>
+func (v Vec3) ZX() Vec2
+
+//gxsl:extern .zy
+//gx:extern gx::swizzle<2, 1>
+func (v Vec3) ZY() Vec2
+
+//gxsl:extern .zz
+//gx:extern gx::swizzle<2, 2>
+func (v Vec3) ZZ() Vec2
+
+//gxsl:extern .xxx
+//gx:extern gx::swizzle<0, 0, 0>
+func (v Vec3) XXX() Vec3
+
+//gxsl:extern .xxy
+//gx:extern gx::swizzle<0, 0, 1>
+func (v Vec3) XXY() Vec3
+
+//gxsl:extern .xxz
+//gx:extern gx::swizzle<0, 0, 2>
+func (v Vec3) XXZ() Vec3
+
+//gxsl:extern .xyx
+//gx:extern gx::swizzle<0, 1, 0>
+func (v Vec3) XYX() Vec3
+
+//gxsl:extern .xyy
+//gx:extern gx::swizzle<0, 1, 1>
+func (v Vec3) XYY() Vec3
+
+//gxsl:extern .xyz
+//gx:extern gx::swizzle<0, 1, 2>
+func (v Vec3) XYZ() Vec3
+
+//gxsl:extern .xzx
+//gx:extern gx::swizzle<0, 2, 0>
+func (v Vec3) XZX() Vec3
+
+//gxsl:extern .xzy
+//gx:extern gx::swizzle<0, 2, 1>
+func (v Vec3) XZY() Vec3
+
+//gxsl:extern .xzz
+//gx:extern gx::swizzle<0, 2, 2>
+func (v Vec3) XZZ() Vec3
+
+//gxsl:extern .yxx
+//gx:extern gx::swizzle<1, 0, 0>
+func (v Vec3) YXX() Vec3
+
+//gxsl:extern .yxy
+//gx:extern gx::swizzle<1, 0, 1>
+func (v Vec3) YXY() Vec3
+
+//gxsl:extern .yxz
+//gx:extern gx::swizzle<1, 0, 2>
+func (v Vec3) YXZ() Vec3
+
+//gxsl:extern .yyx
+//gx:extern gx::swizzle<1, 1, 0>
+func (v Vec3) YYX() Vec3
+
+//gxsl:extern .yyy
+//gx:extern gx::swizzle<1, 1, 1>
+func (v Vec3) YYY() Vec3
+
+//gxsl:extern .yyz
+//gx:extern gx::swizzle<1, 1, 2>
+func (v Vec3) YYZ() Vec3
+
+//gxsl:extern .yzx
+//gx:extern gx::swizzle<1, 2, 0>
+func (v Vec3) YZX() Vec3
+
+//gxsl:extern .yzy
+//gx:extern gx::swizzle<1, 2, 1>
+func (v Vec3) YZY() Vec3
+
+//gxsl:extern .yzz
+//gx:extern gx::swizzle<1, 2, 2>
+func (v Vec3) YZZ() Vec3
+
+//gxsl:extern .zxx
+//gx:extern gx::swizzle<2, 0, 0>
+func (v Vec3) ZXX() Vec3
+
+//gxsl:extern .zxy
+//gx:extern gx::swizzle<2, 0, 1>
+func (v Vec3) ZXY() Vec3
+
+//gxsl:extern .zxz
+//gx:extern gx::swizzle<2, 0, 2>
+func (v Vec3) ZXZ() Vec3
+
+//gxsl:extern .zyx
+//gx:extern gx::swizzle<2, 1, 0>
+func (v Vec3) ZYX() Vec3
+
+//gxsl:extern .zyy
+//gx:extern gx::swizzle<2, 1, 1>
+func (v Vec3) ZYY() Vec3
+
+//gxsl:extern .zyz
+//gx:extern gx::swizzle<2, 1, 2>
+func (v Vec3) ZYZ() Vec3
+
+//gxsl:extern .zzx
+//gx:extern gx::swizzle<2, 2, 0>
+func (v Vec3) ZZX() Vec3
+
+//gxsl:extern .zzy
+//gx:extern gx::swizzle<2, 2, 1>
+func (v Vec3) ZZY() Vec3
+
+//gxsl:extern .zzz
+//gx:extern gx::swizzle<2, 2, 2>
+func (v Vec3) ZZZ() Vec3
+
+//gxsl:extern .xxxx
+//gx:extern gx::swizzle<0, 0, 0, 0>
+func (v Vec3) XXXX() Vec4
+
+//gxsl:extern .xxxy
+//gx:extern gx::swizzle<0, 0, 0, 1>
+func (v Vec3) XXXY() Vec4
+
+//gxsl:extern .xxxz
+//gx:extern gx::swizzle<0, 0, 0, 2>
+func (v Vec3) XXXZ() Vec4
+
+//gxsl:extern .xxyx
+//gx:extern gx::swizzle<0, 0, 1, 0>
+func (v Vec3) XXYX() Vec4
+
+//gxsl:extern .xxyy
+//gx:extern gx::swizzle<0, 0, 1, 1>
+func (v Vec3) XXYY() Vec4
+
+//gxsl:extern .xxyz
+//gx:extern gx::swizzle<0, 0, 1, 2>
+func (v Vec3) XXYZ() Vec4
+
+//gxsl:extern .xxzx
+//gx:extern gx::swizzle<0, 0, 2, 0>
+func (v Vec3) XXZX() Vec4
+
+//gxsl:extern .xxzy
+//gx:extern gx::swizzle<0, 0, 2, 1>
+func (v Vec3) XXZY() Vec4
+
+//gxsl:extern .xxzz
+//gx:extern gx::swizzle<0, 0, 2, 2>
+func (v Vec3) XXZZ() Vec4
+
+//gxsl:extern .xyxx
+//gx:extern gx::swizzle<0, 1, 0, 0>
+func (v Vec3) XYXX() Vec4
+
+//gxsl:extern .xyxy
+//gx:extern gx::swizzle<0, 1, 0, 1>
+func (v Vec3) XYXY() Vec4
+
+//gxsl:extern .xyxz
+//gx:extern gx::swizzle<0, 1, 0, 2>
+func (v Vec3) XYXZ() Vec4
+
+//gxsl:extern .xyyx
+//gx:extern gx::swizzle<0, 1, 1, 0>
+func (v Vec3) XYYX() Vec4
+
+//gxsl:extern .xyyy
+//gx:extern gx::swizzle<0, 1, 1, 1>
+func (v Vec3) XYYY() Vec4
+
+//gxsl:extern .xyyz
+//gx:extern gx::swizzle<0, 1, 1, 2>
+func (v Vec3) XYYZ() Vec4
+
+//gxsl:extern .xyzx
+//gx:extern gx::swizzle<0, 1, 2, 0>
+func (v Vec3) XYZX() Vec4
+
+//gxsl:extern .xyzy
+//gx:extern gx::swizzle<0, 1, 2, 1>
+func (v Vec3) XYZY() Vec4
+
+//gxsl:extern .xyzz
+//gx:extern gx::swizzle<0, 1, 2, 2>
+func (v Vec3) XYZZ() Vec4
+
+//gxsl:extern .xzxx
+//gx:extern gx::swizzle<0, 2, 0, 0>
+func (v Vec3) XZXX() Vec4
+
+//gxsl:extern .xzxy
+//gx:extern gx::swizzle<0, 2, 0, 1>
+func (v Vec3) XZXY() Vec4
+
+//gxsl:extern .xzxz
+//gx:extern gx::swizzle<0, 2, 0, 2>
+func (v Vec3) XZXZ() Vec4
+
+//gxsl:extern .xzyx
+//gx:extern gx::swizzle<0, 2, 1, 0>
+func (v Vec3) XZYX() Vec4
+
+//gxsl:extern .xzyy
+//gx:extern gx::swizzle<0, 2, 1, 1>
+func (v Vec3) XZYY() Vec4
+
+//gxsl:extern .xzyz
+//gx:extern gx::swizzle<0, 2, 1, 2>
+func (v Vec3) XZYZ() Vec4
+
+//gxsl:extern .xzzx
+//gx:extern gx::swizzle<0, 2, 2, 0>
+func (v Vec3) XZZX() Vec4
+
+//gxsl:extern .xzzy
+//gx:extern gx::swizzle<0, 2, 2, 1>
+func (v Vec3) XZZY() Vec4
+
+//gxsl:extern .xzzz
+//gx:extern gx::swizzle<0, 2, 2, 2>
+func (v Vec3) XZZZ() Vec4
+
+//gxsl:extern .yxxx
+//gx:extern gx::swizzle<1, 0, 0, 0>
+func (v Vec3) YXXX() Vec4
+
+//gxsl:extern .yxxy
+//gx:extern gx::swizzle<1, 0, 0, 1>
+func (v Vec3) YXXY() Vec4
+
+//gxsl:extern .yxxz
+//gx:extern gx::swizzle<1, 0, 0, 2>
+func (v Vec3) YXXZ() Vec4
+
+//gxsl:extern .yxyx
+//gx:extern gx::swizzle<1, 0, 1, 0>
+func (v Vec3) YXYX() Vec4
+
+//gxsl:extern .yxyy
+//gx:extern gx::swizzle<1, 0, 1, 1>
+func (v Vec3) YXYY() Vec4
+
+//gxsl:extern .yxyz
+//gx:extern gx::swizzle<1, 0, 1, 2>
+func (v Vec3) YXYZ() Vec4
+
+//gxsl:extern .yxzx
+//gx:extern gx::swizzle<1, 0, 2, 0>
+func (v Vec3) YXZX() Vec4
+
+//gxsl:extern .yxzy
+//gx:extern gx::swizzle<1, 0, 2, 1>
+func (v Vec3) YXZY() Vec4
+
+//gxsl:extern .yxzz
+//gx:extern gx::swizzle<1, 0, 2, 2>
+func (v Vec3) YXZZ() Vec4
+
+//gxsl:extern .yyxx
+//gx:extern gx::swizzle<1, 1, 0, 0>
+func (v Vec3) YYXX() Vec4
+
+//gxsl:extern .yyxy
+//gx:extern gx::swizzle<1, 1, 0, 1>
+func (v Vec3) YYXY() Vec4
+
+//gxsl:extern .yyxz
+//gx:extern gx::swizzle<1, 1, 0, 2>
+func (v Vec3) YYXZ() Vec4
+
+//gxsl:extern .yyyx
+//gx:extern gx::swizzle<1, 1, 1, 0>
+func (v Vec3) YYYX() Vec4
+
+//gxsl:extern .yyyy
+//gx:extern gx::swizzle<1, 1, 1, 1>
+func (v Vec3) YYYY() Vec4
+
+//gxsl:extern .yyyz
+//gx:extern gx::swizzle<1, 1, 1, 2>
+func (v Vec3) YYYZ() Vec4
+
+//gxsl:extern .yyzx
+//gx:extern gx::swizzle<1, 1, 2, 0>
+func (v Vec3) YYZX() Vec4
+
+//gxsl:extern .yyzy
+//gx:extern gx::swizzle<1, 1, 2, 1>
+func (v Vec3) YYZY() Vec4
+
+//gxsl:extern .yyzz
+//gx:extern gx::swizzle<1, 1, 2, 2>
+func (v Vec3) YYZZ() Vec4
+
+//gxsl:extern .yzxx
+//gx:extern gx::swizzle<1, 2, 0, 0>
+func (v Vec3) YZXX() Vec4
+
+//gxsl:extern .yzxy
+//gx:extern gx::swizzle<1, 2, 0, 1>
+func (v Vec3) YZXY() Vec4
+
+//gxsl:extern .yzxz
+//gx:extern gx::swizzle<1, 2, 0, 2>
+func (v Vec3) YZXZ() Vec4
+
+//gxsl:extern .yzyx
+//gx:extern gx::swizzle<1, 2, 1, 0>
+func (v Vec3) YZYX() Vec4
+
+//gxsl:extern .yzyy
+//gx:extern gx::swizzle<1, 2, 1, 1>
+func (v Vec3) YZYY() Vec4
+
+//gxsl:extern .yzyz
+//gx:extern gx::swizzle<1, 2, 1, 2>
+func (v Vec3) YZYZ() Vec4
+
+//gxsl:extern .yzzx
+//gx:extern gx::swizzle<1, 2, 2, 0>
+func (v Vec3) YZZX() Vec4
+
+//gxsl:extern .yzzy
+//gx:extern gx::swizzle<1, 2, 2, 1>
+func (v Vec3) YZZY() Vec4
+
+//gxsl:extern .yzzz
+//gx:extern gx::swizzle<1, 2, 2, 2>
+func (v Vec3) YZZZ() Vec4
+
+//gxsl:extern .zxxx
+//gx:extern gx::swizzle<2, 0, 0, 0>
+func (v Vec3) ZXXX() Vec4
+
+//gxsl:extern .zxxy
+//gx:extern gx::swizzle<2, 0, 0, 1>
+func (v Vec3) ZXXY() Vec4
+
+//gxsl:extern .zxxz
+//gx:extern gx::swizzle<2, 0, 0, 2>
+func (v Vec3) ZXXZ() Vec4
+
+//gxsl:extern .zxyx
+//gx:extern gx::swizzle<2, 0, 1, 0>
+func (v Vec3) ZXYX() Vec4
+
+//gxsl:extern .zxyy
+//gx:extern gx::swizzle<2, 0, 1, 1>
+func (v Vec3) ZXYY() Vec4
+
+//gxsl:extern .zxyz
+//gx:extern gx::swizzle<2, 0, 1, 2>
+func (v Vec3) ZXYZ() Vec4
+
+//gxsl:extern .zxzx
+//gx:extern gx::swizzle<2, 0, 2, 0>
+func (v Vec3) ZXZX() Vec4
+
+//gxsl:extern .zxzy
+//gx:extern gx::swizzle<2, 0, 2, 1>
+func (v Vec3) ZXZY() Vec4
+
+//gxsl:extern .zxzz
+//gx:extern gx::swizzle<2, 0, 2, 2>
+func (v Vec3) ZXZZ() Vec4
+
+//gxsl:extern .zyxx
+//gx:extern gx::swizzle<2, 1, 0, 0>
+func (v Vec3) ZYXX() Vec4
+
+//gxsl:extern .zyxy
+//gx:extern gx::swizzle<2, 1, 0, 1>
+func (v Vec3) ZYXY() Vec4
+
+//gxsl:extern .zyxz
+//gx:extern gx::swizzle<2, 1, 0, 2>
+func (v Vec3) ZYXZ() Vec4
+
+//gxsl:extern .zyyx
+//gx:extern gx::swizzle<2, 1, 1, 0>
+func (v Vec3) ZYYX() Vec4
+
+//gxsl:extern .zyyy
+//gx:extern gx::swizzle<2, 1, 1, 1>
+func (v Vec3) ZYYY() Vec4
+
+//gxsl:extern .zyyz
+//gx:extern gx::swizzle<2, 1, 1, 2>
+func (v Vec3) ZYYZ() Vec4
+
+//gxsl:extern .zyzx
+//gx:extern gx::swizzle<2, 1, 2, 0>
+func (v Vec3) ZYZX() Vec4
+
+//gxsl:extern .zyzy
+//gx:extern gx::swizzle<2, 1, 2, 1>
+func (v Vec3) ZYZY() Vec4
+
+//gxsl:extern .zyzz
+//gx:extern gx::swizzle<2, 1, 2, 2>
+func (v Vec3) ZYZZ() Vec4
+
+//gxsl:extern .zzxx
+//gx:extern gx::swizzle<2, 2, 0, 0>
+func (v Vec3) ZZXX() Vec4
+
+//gxsl:extern .zzxy
+//gx:extern gx::swizzle<2, 2, 0, 1>
+func (v Vec3) ZZXY() Vec4
+
+//gxsl:extern .zzxz
+//gx:extern gx::swizzle<2, 2, 0, 2>
+func (v Vec3) ZZXZ() Vec4
+
+//gxsl:extern .zzyx
+//gx:extern gx::swizzle<2, 2, 1, 0>
+func (v Vec3) ZZYX() Vec4
+
+//gxsl:extern .zzyy
+//gx:extern gx::swizzle<2, 2, 1, 1>
+func (v Vec3) ZZYY() Vec4
+
+//gxsl:extern .zzyz
+//gx:extern gx::swizzle<2, 2, 1, 2>
+func (v Vec3) ZZYZ() Vec4
+
+//gxsl:extern .zzzx
+//gx:extern gx::swizzle<2, 2, 2, 0>
+func (v Vec3) ZZZX() Vec4
+
+//gxsl:extern .zzzy
+//gx:extern gx::swizzle<2, 2, 2, 1>
+func (v Vec3) ZZZY() Vec4
+
+//gxsl:extern .zzzz
+//gx:extern gx::swizzle<2, 2, 2, 2>
+func (v Vec3) ZZZZ() Vec4
+
+//gxsl:extern .xx
+//gx:extern gx::swizzle<0, 0>
+func (v Vec4) XX() Vec2
+
+//gxsl:extern .xy
+//gx:extern gx::swizzle<0, 1>
+func (v Vec4) XY() Vec2
+
+//gxsl:extern .xz
+//gx:extern gx::swizzle<0, 2>
+func (v Vec4) XZ() Vec2
+
+//gxsl:extern .xw
+//gx:extern gx::swizzle<0, 3>
+func (v Vec4) XW() Vec2
+
+//gxsl:extern .yx
+//gx:extern gx::swizzle<1, 0>
+func (v Vec4) YX() Vec2
+
+//gxsl:extern .yy
+//gx:extern gx::swizzle<1, 1>
+func (v Vec4) YY() Vec2
+
+//gxsl:extern .yz
+//gx:extern gx::swizzle<1, 2>
+func (v Vec4) YZ() Vec2
+
+//gxsl:extern .yw
+//gx:extern gx::swizzle<1, 3>
+func (v Vec4) YW() Vec2
+
+//gxsl:extern .zx
+//gx:extern gx::swizzle<2, 0>
+func (v Vec4) ZX() Vec2
+
+//gxsl:extern .zy
+//gx:extern gx::swizzle<2, 1>
+func (v Vec4) ZY() Vec2
+
+//gxsl:extern .zz
+//gx:extern gx::swizzle<2, 2>
+func (v Vec4) ZZ() Vec2
+
+//gxsl:extern .zw
+//gx:extern gx::swizzle<2, 3>
+func (v Vec4) ZW() Vec2
+
+//gxsl:extern .wx
+//gx:extern gx::swizzle<3, 0>
+func (v Vec4) WX() Vec2
+
+//gxsl:extern .wy
+//gx:extern gx::swizzle<3, 1>
+func (v Vec4) WY() Vec2
+
+//gxsl:extern .wz
+//gx:extern gx::swizzle<3, 2>
+func (v Vec4) WZ() Vec2
+
+//gxsl:extern .ww
+//gx:extern gx::swizzle<3, 3>
+func (v Vec4) WW() Vec2
+
+//gxsl:extern .xxx
+//gx:extern gx::swizzle<0, 0, 0>
+func (v Vec4) XXX() Vec3
+
+//gxsl:extern .xxy
+//gx:extern gx::swizzle<0, 0, 1>
+func (v Vec4) XXY() Vec3
+
+//gxsl:extern .xxz
+//gx:extern gx::swizzle<0, 0, 2>
+func (v Vec4) XXZ() Vec3
+
+//gxsl:extern .xxw
+//gx:extern gx::swizzle<0, 0, 3>
+func (v Vec4) XXW() Vec3
+
+//gxsl:extern .xyx
+//gx:extern gx::swizzle<0, 1, 0>
+func (v Vec4) XYX() Vec3
+
+//gxsl:extern .xyy
+//gx:extern gx::swizzle<0, 1, 1>
+func (v Vec4) XYY() Vec3
+
+//gxsl:extern .xyz
+//gx:extern gx::swizzle<0, 1, 2>
+func (v Vec4) XYZ() Vec3
+
+//gxsl:extern .xyw
+//gx:extern gx::swizzle<0, 1, 3>
+func (v Vec4) XYW() Vec3
+
+//gxsl:extern .xzx
+//gx:extern gx::swizzle<0, 2, 0>
+func (v Vec4) XZX() Vec3
+
+//gxsl:extern .xzy
+//gx:extern gx::swizzle<0, 2, 1>
+func (v Vec4) XZY() Vec3
+
+//gxsl:extern .xzz
+//gx:extern gx::swizzle<0, 2, 2>
+func (v Vec4) XZZ() Vec3
+
+//gxsl:extern .xzw
+//gx:extern gx::swizzle<0, 2, 3>
+func (v Vec4) XZW() Vec3
+
+//gxsl:extern .xwx
+//gx:extern gx::swizzle<0, 3, 0>
+func (v Vec4) XWX() Vec3
+
+//gxsl:extern .xwy
+//gx:extern gx::swizzle<0, 3, 1>
+func (v Vec4) XWY() Vec3
+
+//gxsl:extern .xwz
+//gx:extern gx::swizzle<0, 3, 2>
+func (v Vec4) XWZ() Vec3
+
+//gxsl:extern .xww
+//gx:extern gx::swizzle<0, 3, 3>
+func (v Vec4) XWW() Vec3
+
+//gxsl:extern .yxx
+//gx:extern gx::swizzle<1, 0, 0>
+func (v Vec4) YXX() Vec3
+
+//gxsl:extern .yxy
+//gx:extern gx::swizzle<1, 0, 1>
+func (v Vec4) YXY() Vec3
+
+//gxsl:extern .yxz
+//gx:extern gx::swizzle<1, 0, 2>
+func (v Vec4) YXZ() Vec3
+
+//gxsl:extern .yxw
+//gx:extern gx::swizzle<1, 0, 3>
+func (v Vec4) YXW() Vec3
+
+//gxsl:extern .yyx
+//gx:extern gx::swizzle<1, 1, 0>
+func (v Vec4) YYX() Vec3
+
+//gxsl:extern .yyy
+//gx:extern gx::swizzle<1, 1, 1>
+func (v Vec4) YYY() Vec3
+
+//gxsl:extern .yyz
+//gx:extern gx::swizzle<1, 1, 2>
+func (v Vec4) YYZ() Vec3
+
+//gxsl:extern .yyw
+//gx:extern gx::swizzle<1, 1, 3>
+func (v Vec4) YYW() Vec3
+
+//gxsl:extern .yzx
+//gx:extern gx::swizzle<1, 2, 0>
+func (v Vec4) YZX() Vec3
+
+//gxsl:extern .yzy
+//gx:extern gx::swizzle<1, 2, 1>
+func (v Vec4) YZY() Vec3
+
+//gxsl:extern .yzz
+//gx:extern gx::swizzle<1, 2, 2>
+func (v Vec4) YZZ() Vec3
+
+//gxsl:extern .yzw
+//gx:extern gx::swizzle<1, 2, 3>
+func (v Vec4) YZW() Vec3
+
+//gxsl:extern .ywx
+//gx:extern gx::swizzle<1, 3, 0>
+func (v Vec4) YWX() Vec3
+
+//gxsl:extern .ywy
+//gx:extern gx::swizzle<1, 3, 1>
+func (v Vec4) YWY() Vec3
+
+//gxsl:extern .ywz
+//gx:extern gx::swizzle<1, 3, 2>
+func (v Vec4) YWZ() Vec3
+
+//gxsl:extern .yww
+//gx:extern gx::swizzle<1, 3, 3>
+func (v Vec4) YWW() Vec3
+
+//gxsl:extern .zxx
+//gx:extern gx::swizzle<2, 0, 0>
+func (v Vec4) ZXX() Vec3
+
+//gxsl:extern .zxy
+//gx:extern gx::swizzle<2, 0, 1>
+func (v Vec4) ZXY() Vec3
+
+//gxsl:extern .zxz
+//gx:extern gx::swizzle<2, 0, 2>
+func (v Vec4) ZXZ() Vec3
+
+//gxsl:extern .zxw
+//gx:extern gx::swizzle<2, 0, 3>
+func (v Vec4) ZXW() Vec3
+
+//gxsl:extern .zyx
+//gx:extern gx::swizzle<2, 1, 0>
+func (v Vec4) ZYX() Vec3
+
+//gxsl:extern .zyy
+//gx:extern gx::swizzle<2, 1, 1>
+func (v Vec4) ZYY() Vec3
+
+//gxsl:extern .zyz
+//gx:extern gx::swizzle<2, 1, 2>
+func (v Vec4) ZYZ() Vec3
+
+//gxsl:extern .zyw
+//gx:extern gx::swizzle<2, 1, 3>
+func (v Vec4) ZYW() Vec3
+
+//gxsl:extern .zzx
+//gx:extern gx::swizzle<2, 2, 0>
+func (v Vec4) ZZX() Vec3
+
+//gxsl:extern .zzy
+//gx:extern gx::swizzle<2, 2, 1>
+func (v Vec4) ZZY() Vec3
+
+//gxsl:extern .zzz
+//gx:extern gx::swizzle<2, 2, 2>
+func (v Vec4) ZZZ() Vec3
+
+//gxsl:extern .zzw
+//gx:extern gx::swizzle<2, 2, 3>
+func (v Vec4) ZZW() Vec3
+
+//gxsl:extern .zwx
+//gx:extern gx::swizzle<2, 3, 0>
+func (v Vec4) ZWX() Vec3
+
+//gxsl:extern .zwy
+//gx:extern gx::swizzle<2, 3, 1>
+func (v Vec4) ZWY() Vec3
+
+//gxsl:extern .zwz
+//gx:extern gx::swizzle<2, 3, 2>
+func (v Vec4) ZWZ() Vec3
+
+//gxsl:extern .zww
+//gx:extern gx::swizzle<2, 3, 3>
+func (v Vec4) ZWW() Vec3
+
+//gxsl:extern .wxx
+//gx:extern gx::swizzle<3, 0, 0>
+func (v Vec4) WXX() Vec3
+
+//gxsl:extern .wxy
+//gx:extern gx::swizzle<3, 0, 1>
+func (v Vec4) WXY() Vec3
+
+//gxsl:extern .wxz
+//gx:extern gx::swizzle<3, 0, 2>
+func (v Vec4) WXZ() Vec3
+
+//gxsl:extern .wxw
+//gx:extern gx::swizzle<3, 0, 3>
+func (v Vec4) WXW() Vec3
+
+//gxsl:extern .wyx
+//gx:extern gx::swizzle<3, 1, 0>
+func (v Vec4) WYX() Vec3
+
+//gxsl:extern .wyy
+//gx:extern gx::swizzle<3, 1, 1>
+func (v Vec4) WYY() Vec3
+
+//gxsl:extern .wyz
+//gx:extern gx::swizzle<3, 1, 2>
+func (v Vec4) WYZ() Vec3
+
+//gxsl:extern .wyw
+//gx:extern gx::swizzle<3, 1, 3>
+func (v Vec4) WYW() Vec3
+
+//gxsl:extern .wzx
+//gx:extern gx::swizzle<3, 2, 0>
+func (v Vec4) WZX() Vec3
+
+//gxsl:extern .wzy
+//gx:extern gx::swizzle<3, 2, 1>
+func (v Vec4) WZY() Vec3
+
+//gxsl:extern .wzz
+//gx:extern gx::swizzle<3, 2, 2>
+func (v Vec4) WZZ() Vec3
+
+//gxsl:extern .wzw
+//gx:extern gx::swizzle<3, 2, 3>
+func (v Vec4) WZW() Vec3
+
+//gxsl:extern .wwx
+//gx:extern gx::swizzle<3, 3, 0>
+func (v Vec4) WWX() Vec3
+
+//gxsl:extern .wwy
+//gx:extern gx::swizzle<3, 3, 1>
+func (v Vec4) WWY() Vec3
+
+//gxsl:extern .wwz
+//gx:extern gx::swizzle<3, 3, 2>
+func (v Vec4) WWZ() Vec3
+
+//gxsl:extern .www
+//gx:extern gx::swizzle<3, 3, 3>
+func (v Vec4) WWW() Vec3
+
+//gxsl:extern .xxxx
+//gx:extern gx::swizzle<0, 0, 0, 0>
+func (v Vec4) XXXX() Vec4
+
+//gxsl:extern .xxxy
+//gx:extern gx::swizzle<0, 0, 0, 1>
+func (v Vec4) XXXY() Vec4
+
+//gxsl:extern .xxxz
+//gx:extern gx::swizzle<0, 0, 0, 2>
+func (v Vec4) XXXZ() Vec4
+
+//gxsl:extern .xxxw
+//gx:extern gx::swizzle<0, 0, 0, 3>
+func (v Vec4) XXXW() Vec4
+
+//gxsl:extern .xxyx
+//gx:extern gx::swizzle<0, 0, 1, 0>
+func (v Vec4) XXYX() Vec4
+
+//gxsl:extern .xxyy
+//gx:extern gx::swizzle<0, 0, 1, 1>
+func (v Vec4) XXYY() Vec4
+
+//gxsl:extern .xxyz
+//gx:extern gx::swizzle<0, 0, 1, 2>
+func (v Vec4) XXYZ() Vec4
+
+//gxsl:extern .xxyw
+//gx:extern gx::swizzle<0, 0, 1, 3>
+func (v Vec4) XXYW() Vec4
+
+//gxsl:extern .xxzx
+//gx:extern gx::swizzle<0, 0, 2, 0>
+func (v Vec4) XXZX() Vec4
+
+//gxsl:extern .xxzy
+//gx:extern gx::swizzle<0, 0, 2, 1>
+func (v Vec4) XXZY() Vec4
+
+//gxsl:extern .xxzz
+//gx:extern gx::swizzle<0, 0, 2, 2>
+func (v Vec4) XXZZ() Vec4
+
+//gxsl:extern .xxzw
+//gx:extern gx::swizzle<0, 0, 2, 3>
+func (v Vec4) XXZW() Vec4
+
+//gxsl:extern .xxwx
+//gx:extern gx::swizzle<0, 0, 3, 0>
+func (v Vec4) XXWX() Vec4
+
+//gxsl:extern .xxwy
+//gx:extern gx::swizzle<0, 0, 3, 1>
+func (v Vec4) XXWY() Vec4
+
+//gxsl:extern .xxwz
+//gx:extern gx::swizzle<0, 0, 3, 2>
+func (v Vec4) XXWZ() Vec4
+
+//gxsl:extern .xxww
+//gx:extern gx::swizzle<0, 0, 3, 3>
+func (v Vec4) XXWW() Vec4
+
+//gxsl:extern .xyxx
+//gx:extern gx::swizzle<0, 1, 0, 0>
+func (v Vec4) XYXX() Vec4
+
+//gxsl:extern .xyxy
+//gx:extern gx::swizzle<0, 1, 0, 1>
+func (v Vec4) XYXY() Vec4
+
+//gxsl:extern .xyxz
+//gx:extern gx::swizzle<0, 1, 0, 2>
+func (v Vec4) XYXZ() Vec4
+
+//gxsl:extern .xyxw
+//gx:extern gx::swizzle<0, 1, 0, 3>
+func (v Vec4) XYXW() Vec4
+
+//gxsl:extern .xyyx
+//gx:extern gx::swizzle<0, 1, 1, 0>
+func (v Vec4) XYYX() Vec4
+
+//gxsl:extern .xyyy
+//gx:extern gx::swizzle<0, 1, 1, 1>
+func (v Vec4) XYYY() Vec4
+
+//gxsl:extern .xyyz
+//gx:extern gx::swizzle<0, 1, 1, 2>
+func (v Vec4) XYYZ() Vec4
+
+//gxsl:extern .xyyw
+//gx:extern gx::swizzle<0, 1, 1, 3>
+func (v Vec4) XYYW() Vec4
+
+//gxsl:extern .xyzx
+//gx:extern gx::swizzle<0, 1, 2, 0>
+func (v Vec4) XYZX() Vec4
+
+//gxsl:extern .xyzy
+//gx:extern gx::swizzle<0, 1, 2, 1>
+func (v Vec4) XYZY() Vec4
+
+//gxsl:extern .xyzz
+//gx:extern gx::swizzle<0, 1, 2, 2>
+func (v Vec4) XYZZ() Vec4
+
+//gxsl:extern .xyzw
+//gx:extern gx::swizzle<0, 1, 2, 3>
+func (v Vec4) XYZW() Vec4
+
+//gxsl:extern .xywx
+//gx:extern gx::swizzle<0, 1, 3, 0>
+func (v Vec4) XYWX() Vec4
+
+//gxsl:extern .xywy
+//gx:extern gx::swizzle<0, 1, 3, 1>
+func (v Vec4) XYWY() Vec4
+
+//gxsl:extern .xywz
+//gx:extern gx::swizzle<0, 1, 3, 2>
+func (v Vec4) XYWZ() Vec4
+
+//gxsl:extern .xyww
+//gx:extern gx::swizzle<0, 1, 3, 3>
+func (v Vec4) XYWW() Vec4
+
+//gxsl:extern .xzxx
+//gx:extern gx::swizzle<0, 2, 0, 0>
+func (v Vec4) XZXX() Vec4
+
+//gxsl:extern .xzxy
+//gx:extern gx::swizzle<0, 2, 0, 1>
+func (v Vec4) XZXY() Vec4
+
+//gxsl:extern .xzxz
+//gx:extern gx::swizzle<0, 2, 0, 2>
+func (v Vec4) XZXZ() Vec4
+
+//gxsl:extern .xzxw
+//gx:extern gx::swizzle<0, 2, 0, 3>
+func (v Vec4) XZXW() Vec4
+
+//gxsl:extern .xzyx
+//gx:extern gx::swizzle<0, 2, 1, 0>
+func (v Vec4) XZYX() Vec4
+
+//gxsl:extern .xzyy
+//gx:extern gx::swizzle<0, 2, 1, 1>
+func (v Vec4) XZYY() Vec4
+
+//gxsl:extern .xzyz
+//gx:extern gx::swizzle<0, 2, 1, 2>
+func (v Vec4) XZYZ() Vec4
+
+//gxsl:extern .xzyw
+//gx:extern gx::swizzle<0, 2, 1, 3>
+func (v Vec4) XZYW() Vec4
+
+//gxsl:extern .xzzx
+//gx:extern gx::swizzle<0, 2, 2, 0>
+func (v Vec4) XZZX() Vec4
+
+//gxsl:extern .xzzy
+//gx:extern gx::swizzle<0, 2, 2, 1>
+func (v Vec4) XZZY() Vec4
+
+//gxsl:extern .xzzz
+//gx:extern gx::swizzle<0, 2, 2, 2>
+func (v Vec4) XZZZ() Vec4
+
+//gxsl:extern .xzzw
+//gx:extern gx::swizzle<0, 2, 2, 3>
+func (v Vec4) XZZW() Vec4
+
+//gxsl:extern .xzwx
+//gx:extern gx::swizzle<0, 2, 3, 0>
+func (v Vec4) XZWX() Vec4
+
+//gxsl:extern .xzwy
+//gx:extern gx::swizzle<0, 2, 3, 1>
+func (v Vec4) XZWY() Vec4
+
+//gxsl:extern .xzwz
+//gx:extern gx::swizzle<0, 2, 3, 2>
+func (v Vec4) XZWZ() Vec4
+
+//gxsl:extern .xzww
+//gx:extern gx::swizzle<0, 2, 3, 3>
+func (v Vec4) XZWW() Vec4
+
+//gxsl:extern .xwxx
+//gx:extern gx::swizzle<0, 3, 0, 0>
+func (v Vec4) XWXX() Vec4
+
+//gxsl:extern .xwxy
+//gx:extern gx::swizzle<0, 3, 0, 1>
+func (v Vec4) XWXY() Vec4
+
+//gxsl:extern .xwxz
+//gx:extern gx::swizzle<0, 3, 0, 2>
+func (v Vec4) XWXZ() Vec4
+
+//gxsl:extern .xwxw
+//gx:extern gx::swizzle<0, 3, 0, 3>
+func (v Vec4) XWXW() Vec4
+
+//gxsl:extern .xwyx
+//gx:extern gx::swizzle<0, 3, 1, 0>
+func (v Vec4) XWYX() Vec4
+
+//gxsl:extern .xwyy
+//gx:extern gx::swizzle<0, 3, 1, 1>
+func (v Vec4) XWYY() Vec4
+
+//gxsl:extern .xwyz
+//gx:extern gx::swizzle<0, 3, 1, 2>
+func (v Vec4) XWYZ() Vec4
+
+//gxsl:extern .xwyw
+//gx:extern gx::swizzle<0, 3, 1, 3>
+func (v Vec4) XWYW() Vec4
+
+//gxsl:extern .xwzx
+//gx:extern gx::swizzle<0, 3, 2, 0>
+func (v Vec4) XWZX() Vec4
+
+//gxsl:extern .xwzy
+//gx:extern gx::swizzle<0, 3, 2, 1>
+func (v Vec4) XWZY() Vec4
+
+//gxsl:extern .xwzz
+//gx:extern gx::swizzle<0, 3, 2, 2>
+func (v Vec4) XWZZ() Vec4
+
+//gxsl:extern .xwzw
+//gx:extern gx::swizzle<0, 3, 2, 3>
+func (v Vec4) XWZW() Vec4
+
+//gxsl:extern .xwwx
+//gx:extern gx::swizzle<0, 3, 3, 0>
+func (v Vec4) XWWX() Vec4
+
+//gxsl:extern .xwwy
+//gx:extern gx::swizzle<0, 3, 3, 1>
+func (v Vec4) XWWY() Vec4
+
+//gxsl:extern .xwwz
+//gx:extern gx::swizzle<0, 3, 3, 2>
+func (v Vec4) XWWZ() Vec4
+
+//gxsl:extern .xwww
+//gx:extern gx::swizzle<0, 3, 3, 3>
+func (v Vec4) XWWW() Vec4
+
+//gxsl:extern .yxxx
+//gx:extern gx::swizzle<1, 0, 0, 0>
+func (v Vec4) YXXX() Vec4
+
+//gxsl:extern .yxxy
+//gx:extern gx::swizzle<1, 0, 0, 1>
+func (v Vec4) YXXY() Vec4
+
+//gxsl:extern .yxxz
+//gx:extern gx::swizzle<1, 0, 0, 2>
+func (v Vec4) YXXZ() Vec4
+
+//gxsl:extern .yxxw
+//gx:extern gx::swizzle<1, 0, 0, 3>
+func (v Vec4) YXXW() Vec4
+
+//gxsl:extern .yxyx
+//gx:extern gx::swizzle<1, 0, 1, 0>
+func (v Vec4) YXYX() Vec4
+
+//gxsl:extern .yxyy
+//gx:extern gx::swizzle<1, 0, 1, 1>
+func (v Vec4) YXYY() Vec4
+
+//gxsl:extern .yxyz
+//gx:extern gx::swizzle<1, 0, 1, 2>
+func (v Vec4) YXYZ() Vec4
+
+//gxsl:extern .yxyw
+//gx:extern gx::swizzle<1, 0, 1, 3>
+func (v Vec4) YXYW() Vec4
+
+//gxsl:extern .yxzx
+//gx:extern gx::swizzle<1, 0, 2, 0>
+func (v Vec4) YXZX() Vec4
+
+//gxsl:extern .yxzy
+//gx:extern gx::swizzle<1, 0, 2, 1>
+func (v Vec4) YXZY() Vec4
+
+//gxsl:extern .yxzz
+//gx:extern gx::swizzle<1, 0, 2, 2>
+func (v Vec4) YXZZ() Vec4
+
+//gxsl:extern .yxzw
+//gx:extern gx::swizzle<1, 0, 2, 3>
+func (v Vec4) YXZW() Vec4
+
+//gxsl:extern .yxwx
+//gx:extern gx::swizzle<1, 0, 3, 0>
+func (v Vec4) YXWX() Vec4
+
+//gxsl:extern .yxwy
+//gx:extern gx::swizzle<1, 0, 3, 1>
+func (v Vec4) YXWY() Vec4
+
+//gxsl:extern .yxwz
+//gx:extern gx::swizzle<1, 0, 3, 2>
+func (v Vec4) YXWZ() Vec4
+
+//gxsl:extern .yxww
+//gx:extern gx::swizzle<1, 0, 3, 3>
+func (v Vec4) YXWW() Vec4
+
+//gxsl:extern .yyxx
+//gx:extern gx::swizzle<1, 1, 0, 0>
+func (v Vec4) YYXX() Vec4
+
+//gxsl:extern .yyxy
+//gx:extern gx::swizzle<1, 1, 0, 1>
+func (v Vec4) YYXY() Vec4
+
+//gxsl:extern .yyxz
+//gx:extern gx::swizzle<1, 1, 0, 2>
+func (v Vec4) YYXZ() Vec4
+
+//gxsl:extern .yyxw
+//gx:extern gx::swizzle<1, 1, 0, 3>
+func (v Vec4) YYXW() Vec4
+
+//gxsl:extern .yyyx
+//gx:extern gx::swizzle<1, 1, 1, 0>
+func (v Vec4) YYYX() Vec4
+
+//gxsl:extern .yyyy
+//gx:extern gx::swizzle<1, 1, 1, 1>
+func (v Vec4) YYYY() Vec4
+
+//gxsl:extern .yyyz
+//gx:extern gx::swizzle<1, 1, 1, 2>
+func (v Vec4) YYYZ() Vec4
+
+//gxsl:extern .yyyw
+//gx:extern gx::swizzle<1, 1, 1, 3>
+func (v Vec4) YYYW() Vec4
+
+//gxsl:extern .yyzx
+//gx:extern gx::swizzle<1, 1, 2, 0>
+func (v Vec4) YYZX() Vec4
+
+//gxsl:extern .yyzy
+//gx:extern gx::swizzle<1, 1, 2, 1>
+func (v Vec4) YYZY() Vec4
+
+//gxsl:extern .yyzz
+//gx:extern gx::swizzle<1, 1, 2, 2>
+func (v Vec4) YYZZ() Vec4
+
+//gxsl:extern .yyzw
+//gx:extern gx::swizzle<1, 1, 2, 3>
+func (v Vec4) YYZW() Vec4
+
+//gxsl:extern .yywx
+//gx:extern gx::swizzle<1, 1, 3, 0>
+func (v Vec4) YYWX() Vec4
+
+//gxsl:extern .yywy
+//gx:extern gx::swizzle<1, 1, 3, 1>
+func (v Vec4) YYWY() Vec4
+
+//gxsl:extern .yywz
+//gx:extern gx::swizzle<1, 1, 3, 2>
+func (v Vec4) YYWZ() Vec4
+
+//gxsl:extern .yyww
+//gx:extern gx::swizzle<1, 1, 3, 3>
+func (v Vec4) YYWW() Vec4
+
+//gxsl:extern .yzxx
+//gx:extern gx::swizzle<1, 2, 0, 0>
+func (v Vec4) YZXX() Vec4
+
+//gxsl:extern .yzxy
+//gx:extern gx::swizzle<1, 2, 0, 1>
+func (v Vec4) YZXY() Vec4
+
+//gxsl:extern .yzxz
+//gx:extern gx::swizzle<1, 2, 0, 2>
+func (v Vec4) YZXZ() Vec4
+
+//gxsl:extern .yzxw
+//gx:extern gx::swizzle<1, 2, 0, 3>
+func (v Vec4) YZXW() Vec4
+
+//gxsl:extern .yzyx
+//gx:extern gx::swizzle<1, 2, 1, 0>
+func (v Vec4) YZYX() Vec4
+
+//gxsl:extern .yzyy
+//gx:extern gx::swizzle<1, 2, 1, 1>
+func (v Vec4) YZYY() Vec4
+
+//gxsl:extern .yzyz
+//gx:extern gx::swizzle<1, 2, 1, 2>
+func (v Vec4) YZYZ() Vec4
+
+//gxsl:extern .yzyw
+//gx:extern gx::swizzle<1, 2, 1, 3>
+func (v Vec4) YZYW() Vec4
+
+//gxsl:extern .yzzx
+//gx:extern gx::swizzle<1, 2, 2, 0>
+func (v Vec4) YZZX() Vec4
+
+//gxsl:extern .yzzy
+//gx:extern gx::swizzle<1, 2, 2, 1>
+func (v Vec4) YZZY() Vec4
+
+//gxsl:extern .yzzz
+//gx:extern gx::swizzle<1, 2, 2, 2>
+func (v Vec4) YZZZ() Vec4
+
+//gxsl:extern .yzzw
+//gx:extern gx::swizzle<1, 2, 2, 3>
+func (v Vec4) YZZW() Vec4
+
+//gxsl:extern .yzwx
+//gx:extern gx::swizzle<1, 2, 3, 0>
+func (v Vec4) YZWX() Vec4
+
+//gxsl:extern .yzwy
+//gx:extern gx::swizzle<1, 2, 3, 1>
+func (v Vec4) YZWY() Vec4
+
+//gxsl:extern .yzwz
+//gx:extern gx::swizzle<1, 2, 3, 2>
+func (v Vec4) YZWZ() Vec4
+
+//gxsl:extern .yzww
+//gx:extern gx::swizzle<1, 2, 3, 3>
+func (v Vec4) YZWW() Vec4
+
+//gxsl:extern .ywxx
+//gx:extern gx::swizzle<1, 3, 0, 0>
+func (v Vec4) YWXX() Vec4
+
+//gxsl:extern .ywxy
+//gx:extern gx::swizzle<1, 3, 0, 1>
+func (v Vec4) YWXY() Vec4
+
+//gxsl:extern .ywxz
+//gx:extern gx::swizzle<1, 3, 0, 2>
+func (v Vec4) YWXZ() Vec4
+
+//gxsl:extern .ywxw
+//gx:extern gx::swizzle<1, 3, 0, 3>
+func (v Vec4) YWXW() Vec4
+
+//gxsl:extern .ywyx
+//gx:extern gx::swizzle<1, 3, 1, 0>
+func (v Vec4) YWYX() Vec4
+
+//gxsl:extern .ywyy
+//gx:extern gx::swizzle<1, 3, 1, 1>
+func (v Vec4) YWYY() Vec4
+
+//gxsl:extern .ywyz
+//gx:extern gx::swizzle<1, 3, 1, 2>
+func (v Vec4) YWYZ() Vec4
+
+//gxsl:extern .ywyw
+//gx:extern gx::swizzle<1, 3, 1, 3>
+func (v Vec4) YWYW() Vec4
+
+//gxsl:extern .ywzx
+//gx:extern gx::swizzle<1, 3, 2, 0>
+func (v Vec4) YWZX() Vec4
+
+//gxsl:extern .ywzy
+//gx:extern gx::swizzle<1, 3, 2, 1>
+func (v Vec4) YWZY() Vec4
+
+//gxsl:extern .ywzz
+//gx:extern gx::swizzle<1, 3, 2, 2>
+func (v Vec4) YWZZ() Vec4
+
+//gxsl:extern .ywzw
+//gx:extern gx::swizzle<1, 3, 2, 3>
+func (v Vec4) YWZW() Vec4
+
+//gxsl:extern .ywwx
+//gx:extern gx::swizzle<1, 3, 3, 0>
+func (v Vec4) YWWX() Vec4
+
+//gxsl:extern .ywwy
+//gx:extern gx::swizzle<1, 3, 3, 1>
+func (v Vec4) YWWY() Vec4
+
+//gxsl:extern .ywwz
+//gx:extern gx::swizzle<1, 3, 3, 2>
+func (v Vec4) YWWZ() Vec4
+
+//gxsl:extern .ywww
+//gx:extern gx::swizzle<1, 3, 3, 3>
+func (v Vec4) YWWW() Vec4
+
+//gxsl:extern .zxxx
+//gx:extern gx::swizzle<2, 0, 0, 0>
+func (v Vec4) ZXXX() Vec4
+
+//gxsl:extern .zxxy
+//gx:extern gx::swizzle<2, 0, 0, 1>
+func (v Vec4) ZXXY() Vec4
+
+//gxsl:extern .zxxz
+//gx:extern gx::swizzle<2, 0, 0, 2>
+func (v Vec4) ZXXZ() Vec4
+
+//gxsl:extern .zxxw
+//gx:extern gx::swizzle<2, 0, 0, 3>
+func (v Vec4) ZXXW() Vec4
+
+//gxsl:extern .zxyx
+//gx:extern gx::swizzle<2, 0, 1, 0>
+func (v Vec4) ZXYX() Vec4
+
+//gxsl:extern .zxyy
+//gx:extern gx::swizzle<2, 0, 1, 1>
+func (v Vec4) ZXYY() Vec4
+
+//gxsl:extern .zxyz
+//gx:extern gx::swizzle<2, 0, 1, 2>
+func (v Vec4) ZXYZ() Vec4
+
+//gxsl:extern .zxyw
+//gx:extern gx::swizzle<2, 0, 1, 3>
+func (v Vec4) ZXYW() Vec4
+
+//gxsl:extern .zxzx
+//gx:extern gx::swizzle<2, 0, 2, 0>
+func (v Vec4) ZXZX() Vec4
+
+//gxsl:extern .zxzy
+//gx:extern gx::swizzle<2, 0, 2, 1>
+func (v Vec4) ZXZY() Vec4
+
+//gxsl:extern .zxzz
+//gx:extern gx::swizzle<2, 0, 2, 2>
+func (v Vec4) ZXZZ() Vec4
+
+//gxsl:extern .zxzw
+//gx:extern gx::swizzle<2, 0, 2, 3>
+func (v Vec4) ZXZW() Vec4
+
+//gxsl:extern .zxwx
+//gx:extern gx::swizzle<2, 0, 3, 0>
+func (v Vec4) ZXWX() Vec4
+
+//gxsl:extern .zxwy
+//gx:extern gx::swizzle<2, 0, 3, 1>
+func (v Vec4) ZXWY() Vec4
+
+//gxsl:extern .zxwz
+//gx:extern gx::swizzle<2, 0, 3, 2>
+func (v Vec4) ZXWZ() Vec4
+
+//gxsl:extern .zxww
+//gx:extern gx::swizzle<2, 0, 3, 3>
+func (v Vec4) ZXWW() Vec4
+
+//gxsl:extern .zyxx
+//gx:extern gx::swizzle<2, 1, 0, 0>
+func (v Vec4) ZYXX() Vec4
+
+//gxsl:extern .zyxy
+//gx:extern gx::swizzle<2, 1, 0, 1>
+func (v Vec4) ZYXY() Vec4
+
+//gxsl:extern .zyxz
+//gx:extern gx::swizzle<2, 1, 0, 2>
+func (v Vec4) ZYXZ() Vec4
+
+//gxsl:extern .zyxw
+//gx:extern gx::swizzle<2, 1, 0, 3>
+func (v Vec4) ZYXW() Vec4
+
+//gxsl:extern .zyyx
+//gx:extern gx::swizzle<2, 1, 1, 0>
+func (v Vec4) ZYYX() Vec4
+
+//gxsl:extern .zyyy
+//gx:extern gx::swizzle<2, 1, 1, 1>
+func (v Vec4) ZYYY() Vec4
+
+//gxsl:extern .zyyz
+//gx:extern gx::swizzle<2, 1, 1, 2>
+func (v Vec4) ZYYZ() Vec4
+
+//gxsl:extern .zyyw
+//gx:extern gx::swizzle<2, 1, 1, 3>
+func (v Vec4) ZYYW() Vec4
+
+//gxsl:extern .zyzx
+//gx:extern gx::swizzle<2, 1, 2, 0>
+func (v Vec4) ZYZX() Vec4
+
+//gxsl:extern .zyzy
+//gx:extern gx::swizzle<2, 1, 2, 1>
+func (v Vec4) ZYZY() Vec4
+
+//gxsl:extern .zyzz
+//gx:extern gx::swizzle<2, 1, 2, 2>
+func (v Vec4) ZYZZ() Vec4
+
+//gxsl:extern .zyzw
+//gx:extern gx::swizzle<2, 1, 2, 3>
+func (v Vec4) ZYZW() Vec4
+
+//gxsl:extern .zywx
+//gx:extern gx::swizzle<2, 1, 3, 0>
+func (v Vec4) ZYWX() Vec4
+
+//gxsl:extern .zywy
+//gx:extern gx::swizzle<2, 1, 3, 1>
+func (v Vec4) ZYWY() Vec4
+
+//gxsl:extern .zywz
+//gx:extern gx::swizzle<2, 1, 3, 2>
+func (v Vec4) ZYWZ() Vec4
+
+//gxsl:extern .zyww
+//gx:extern gx::swizzle<2, 1, 3, 3>
+func (v Vec4) ZYWW() Vec4
+
+//gxsl:extern .zzxx
+//gx:extern gx::swizzle<2, 2, 0, 0>
+func (v Vec4) ZZXX() Vec4
+
+//gxsl:extern .zzxy
+//gx:extern gx::swizzle<2, 2, 0, 1>
+func (v Vec4) ZZXY() Vec4
+
+//gxsl:extern .zzxz
+//gx:extern gx::swizzle<2, 2, 0, 2>
+func (v Vec4) ZZXZ() Vec4
+
+//gxsl:extern .zzxw
+//gx:extern gx::swizzle<2, 2, 0, 3>
+func (v Vec4) ZZXW() Vec4
+
+//gxsl:extern .zzyx
+//gx:extern gx::swizzle<2, 2, 1, 0>
+func (v Vec4) ZZYX() Vec4
+
+//gxsl:extern .zzyy
+//gx:extern gx::swizzle<2, 2, 1, 1>
+func (v Vec4) ZZYY() Vec4
+
+//gxsl:extern .zzyz
+//gx:extern gx::swizzle<2, 2, 1, 2>
+func (v Vec4) ZZYZ() Vec4
+
+//gxsl:extern .zzyw
+//gx:extern gx::swizzle<2, 2, 1, 3>
+func (v Vec4) ZZYW() Vec4
+
+//gxsl:extern .zzzx
+//gx:extern gx::swizzle<2, 2, 2, 0>
+func (v Vec4) ZZZX() Vec4
+
+//gxsl:extern .zzzy
+//gx:extern gx::swizzle<2, 2, 2, 1>
+func (v Vec4) ZZZY() Vec4
+
+//gxsl:extern .zzzz
+//gx:extern gx::swizzle<2, 2, 2, 2>
+func (v Vec4) ZZZZ() Vec4
+
+//gxsl:extern .zzzw
+//gx:extern gx::swizzle<2, 2, 2, 3>
+func (v Vec4) ZZZW() Vec4
+
+//gxsl:extern .zzwx
+//gx:extern gx::swizzle<2, 2, 3, 0>
+func (v Vec4) ZZWX() Vec4
+
+//gxsl:extern .zzwy
+//gx:extern gx::swizzle<2, 2, 3, 1>
+func (v Vec4) ZZWY() Vec4
+
+//gxsl:extern .zzwz
+//gx:extern gx::swizzle<2, 2, 3, 2>
+func (v Vec4) ZZWZ() Vec4
+
+//gxsl:extern .zzww
+//gx:extern gx::swizzle<2, 2, 3, 3>
+func (v Vec4) ZZWW() Vec4
+
+//gxsl:extern .zwxx
+//gx:extern gx::swizzle<2, 3, 0, 0>
+func (v Vec4) ZWXX() Vec4
+
+//gxsl:extern .zwxy
+//gx:extern gx::swizzle<2, 3, 0, 1>
+func (v Vec4) ZWXY() Vec4
+
+//gxsl:extern .zwxz
+//gx:extern gx::swizzle<2, 3, 0, 2>
+func (v Vec4) ZWXZ() Vec4
+
+//gxsl:extern .zwxw
+//gx:extern gx::swizzle<2, 3, 0, 3>
+func (v Vec4) ZWXW() Vec4
+
+//gxsl:extern .zwyx
+//gx:extern gx::swizzle<2, 3, 1, 0>
+func (v Vec4) ZWYX() Vec4
+
+//gxsl:extern .zwyy
+//gx:extern gx::swizzle<2, 3, 1, 1>
+func (v Vec4) ZWYY() Vec4
+
+//gxsl:extern .zwyz
+//gx:extern gx::swizzle<2, 3, 1, 2>
+func (v Vec4) ZWYZ() Vec4
+
+//gxsl:extern .zwyw
+//gx:extern gx::swizzle<2, 3, 1, 3>
+func (v Vec4) ZWYW() Vec4
+
+//gxsl:extern .zwzx
+//gx:extern gx::swizzle<2, 3, 2, 0>
+func (v Vec4) ZWZX() Vec4
+
+//gxsl:extern .zwzy
+//gx:extern gx::swizzle<2, 3, 2, 1>
+func (v Vec4) ZWZY() Vec4
+
+//gxsl:extern .zwzz
+//gx:extern gx::swizzle<2, 3, 2, 2>
+func (v Vec4) ZWZZ() Vec4
+
+//gxsl:extern .zwzw
+//gx:extern gx::swizzle<2, 3, 2, 3>
+func (v Vec4) ZWZW() Vec4
+
+//gxsl:extern .zwwx
+//gx:extern gx::swizzle<2, 3, 3, 0>
+func (v Vec4) ZWWX() Vec4
+
+//gxsl:extern .zwwy
+//gx:extern gx::swizzle<2, 3, 3, 1>
+func (v Vec4) ZWWY() Vec4
+
+//gxsl:extern .zwwz
+//gx:extern gx::swizzle<2, 3, 3, 2>
+func (v Vec4) ZWWZ() Vec4
+
+//gxsl:extern .zwww
+//gx:extern gx::swizzle<2, 3, 3, 3>
+func (v Vec4) ZWWW() Vec4
+
+//gxsl:extern .wxxx
+//gx:extern gx::swizzle<3, 0, 0, 0>
+func (v Vec4) WXXX() Vec4
+
+//gxsl:extern .wxxy
+//gx:extern gx::swizzle<3, 0, 0, 1>
+func (v Vec4) WXXY() Vec4
+
+//gxsl:extern .wxxz
+//gx:extern gx::swizzle<3, 0, 0, 2>
+func (v Vec4) WXXZ() Vec4
+
+//gxsl:extern .wxxw
+//gx:extern gx::swizzle<3, 0, 0, 3>
+func (v Vec4) WXXW() Vec4
+
+//gxsl:extern .wxyx
+//gx:extern gx::swizzle<3, 0, 1, 0>
+func (v Vec4) WXYX() Vec4
+
+//gxsl:extern .wxyy
+//gx:extern gx::swizzle<3, 0, 1, 1>
+func (v Vec4) WXYY() Vec4
+
+//gxsl:extern .wxyz
+//gx:extern gx::swizzle<3, 0, 1, 2>
+func (v Vec4) WXYZ() Vec4
+
+//gxsl:extern .wxyw
+//gx:extern gx::swizzle<3, 0, 1, 3>
+func (v Vec4) WXYW() Vec4
+
+//gxsl:extern .wxzx
+//gx:extern gx::swizzle<3, 0, 2, 0>
+func (v Vec4) WXZX() Vec4
+
+//gxsl:extern .wxzy
+//gx:extern gx::swizzle<3, 0, 2, 1>
+func (v Vec4) WXZY() Vec4
+
+//gxsl:extern .wxzz
+//gx:extern gx::swizzle<3, 0, 2, 2>
+func (v Vec4) WXZZ() Vec4
+
+//gxsl:extern .wxzw
+//gx:extern gx::swizzle<3, 0, 2, 3>
+func (v Vec4) WXZW() Vec4
+
+//gxsl:extern .wxwx
+//gx:extern gx::swizzle<3, 0, 3, 0>
+func (v Vec4) WXWX() Vec4
+
+//gxsl:extern .wxwy
+//gx:extern gx::swizzle<3, 0, 3, 1>
+func (v Vec4) WXWY() Vec4
+
+//gxsl:extern .wxwz
+//gx:extern gx::swizzle<3, 0, 3, 2>
+func (v Vec4) WXWZ() Vec4
+
+//gxsl:extern .wxww
+//gx:extern gx::swizzle<3, 0, 3, 3>
+func (v Vec4) WXWW() Vec4
+
+//gxsl:extern .wyxx
+//gx:extern gx::swizzle<3, 1, 0, 0>
+func (v Vec4) WYXX() Vec4
+
+//gxsl:extern .wyxy
+//gx:extern gx::swizzle<3, 1, 0, 1>
+func (v Vec4) WYXY() Vec4
+
+//gxsl:extern .wyxz
+//gx:extern gx::swizzle<3, 1, 0, 2>
+func (v Vec4) WYXZ() Vec4
+
+//gxsl:extern .wyxw
+//gx:extern gx::swizzle<3, 1, 0, 3>
+func (v Vec4) WYXW() Vec4
+
+//gxsl:extern .wyyx
+//gx:extern gx::swizzle<3, 1, 1, 0>
+func (v Vec4) WYYX() Vec4
+
+//gxsl:extern .wyyy
+//gx:extern gx::swizzle<3, 1, 1, 1>
+func (v Vec4) WYYY() Vec4
+
+//gxsl:extern .wyyz
+//gx:extern gx::swizzle<3, 1, 1, 2>
+func (v Vec4) WYYZ() Vec4
+
+//gxsl:extern .wyyw
+//gx:extern gx::swizzle<3, 1, 1, 3>
+func (v Vec4) WYYW() Vec4
+
+//gxsl:extern .wyzx
+//gx:extern gx::swizzle<3, 1, 2, 0>
+func (v Vec4) WYZX() Vec4
+
+//gxsl:extern .wyzy
+//gx:extern gx::swizzle<3, 1, 2, 1>
+func (v Vec4) WYZY() Vec4
+
+//gxsl:extern .wyzz
+//gx:extern gx::swizzle<3, 1, 2, 2>
+func (v Vec4) WYZZ() Vec4
+
+//gxsl:extern .wyzw
+//gx:extern gx::swizzle<3, 1, 2, 3>
+func (v Vec4) WYZW() Vec4
+
+//gxsl:extern .wywx
+//gx:extern gx::swizzle<3, 1, 3, 0>
+func (v Vec4) WYWX() Vec4
+
+//gxsl:extern .wywy
+//gx:extern gx::swizzle<3, 1, 3, 1>
+func (v Vec4) WYWY() Vec4
+
+//gxsl:extern .wywz
+//gx:extern gx::swizzle<3, 1, 3, 2>
+func (v Vec4) WYWZ() Vec4
+
+//gxsl:extern .wyww
+//gx:extern gx::swizzle<3, 1, 3, 3>
+func (v Vec4) WYWW() Vec4
+
+//gxsl:extern .wzxx
+//gx:extern gx::swizzle<3, 2, 0, 0>
+func (v Vec4) WZXX() Vec4
+
+//gxsl:extern .wzxy
+//gx:extern gx::swizzle<3, 2, 0, 1>
+func (v Vec4) WZXY() Vec4
+
+//gxsl:extern .wzxz
+//gx:extern gx::swizzle<3, 2, 0, 2>
+func (v Vec4) WZXZ() Vec4
+
+//gxsl:extern .wzxw
+//gx:extern gx::swizzle<3, 2, 0, 3>
+func (v Vec4) WZXW() Vec4
+
+//gxsl:extern .wzyx
+//gx:extern gx::swizzle<3, 2, 1, 0>
+func (v Vec4) WZYX() Vec4
+
+//gxsl:extern .wzyy
+//gx:extern gx::swizzle<3, 2, 1, 1>
+func (v Vec4) WZYY() Vec4
+
+//gxsl:extern .wzyz
+//gx:extern gx::swizzle<3, 2, 1, 2>
+func (v Vec4) WZYZ() Vec4
+
+//gxsl:extern .wzyw
+//gx:extern gx::swizzle<3, 2, 1, 3>
+func (v Vec4) WZYW() Vec4
+
+//gxsl:extern .wzzx
+//gx:extern gx::swizzle<3, 2, 2, 0>
+func (v Vec4) WZZX() Vec4
+
+//gxsl:extern .wzzy
+//gx:extern gx::swizzle<3, 2, 2, 1>
+func (v Vec4) WZZY() Vec4
+
+//gxsl:extern .wzzz
+//gx:extern gx::swizzle<3, 2, 2, 2>
+func (v Vec4) WZZZ() Vec4
+
+//gxsl:extern .wzzw
+//gx:extern gx::swizzle<3, 2, 2, 3>
+func (v Vec4) WZZW() Vec4
+
+//gxsl:extern .wzwx
+//gx:extern gx::swizzle<3, 2, 3, 0>
+func (v Vec4) WZWX() Vec4
+
+//gxsl:extern .wzwy
+//gx:extern gx::swizzle<3, 2, 3, 1>
+func (v Vec4) WZWY() Vec4
+
+//gxsl:extern .wzwz
+//gx:extern gx::swizzle<3, 2, 3, 2>
+func (v Vec4) WZWZ() Vec4
+
+//gxsl:extern .wzww
+//gx:extern gx::swizzle<3, 2, 3, 3>
+func (v Vec4) WZWW() Vec4
+
+//gxsl:extern .wwxx
+//gx:extern gx::swizzle<3, 3, 0, 0>
+func (v Vec4) WWXX() Vec4
+
+//gxsl:extern .wwxy
+//gx:extern gx::swizzle<3, 3, 0, 1>
+func (v Vec4) WWXY() Vec4
+
+//gxsl:extern .wwxz
+//gx:extern gx::swizzle<3, 3, 0, 2>
+func (v Vec4) WWXZ() Vec4
+
+//gxsl:extern .wwxw
+//gx:extern gx::swizzle<3, 3, 0, 3>
+func (v Vec4) WWXW() Vec4
+
+//gxsl:extern .wwyx
+//gx:extern gx::swizzle<3, 3, 1, 0>
+func (v Vec4) WWYX() Vec4
+
+//gxsl:extern .wwyy
+//gx:extern gx::swizzle<3, 3, 1, 1>
+func (v Vec4) WWYY() Vec4
+
+//gxsl:extern .wwyz
+//gx:extern gx::swizzle<3, 3, 1, 2>
+func (v Vec4) WWYZ() Vec4
+
+//gxsl:extern .wwyw
+//gx:extern gx::swizzle<3, 3, 1, 3>
+func (v Vec4) WWYW() Vec4
+
+//gxsl:extern .wwzx
+//gx:extern gx::swizzle<3, 3, 2, 0>
+func (v Vec4) WWZX() Vec4
+
+//gxsl:extern .wwzy
+//gx:extern gx::swizzle<3, 3, 2, 1>
+func (v Vec4) WWZY() Vec4
+
+//gxsl:extern .wwzz
+//gx:extern gx::swizzle<3, 3, 2, 2>
+func (v Vec4) WWZZ() Vec4
+
+//gxsl:extern .wwzw
+//gx:extern gx::swizzle<3, 3, 2, 3>
+func (v Vec4) WWZW() Vec4
+
+//gxsl:extern .wwwx
+//gx:extern gx::swizzle<3, 3, 3, 0>
+func (v Vec4) WWWX() Vec4
+
+//gxsl:extern .wwwy
+//gx:extern gx::swizzle<3, 3, 3, 1>
+func (v Vec4) WWWY() Vec4
+
+//gxsl:extern .wwwz
+//gx:extern gx::swizzle<3, 3, 3, 2>
+func (v Vec4) WWWZ() Vec4
+
+//gxsl:extern .wwww
+//gx:extern gx::swizzle<3, 3, 3, 3>
+func (v Vec4) WWWW() Vec4
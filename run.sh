@@ -37,7 +37,7 @@ case "$1" in
     if [[ -f build/example.gx.cc ]]; then
       $CLANG -std=c++20 -Wall -O3 -Iexample -o build/example build/example.*.cc
     fi
-    $TIME ./gx$EXE ./example/gxsl build/example_gxsl build/example_gxsl_ .frag
+    $TIME ./gx$EXE -wgsl -gxsl-cpp ./example/gxsl build/example_gxsl build/example_gxsl_ .frag .vert .comp
     if [[ -f build/example_gxsl.gx.cc ]]; then
       $CLANG -std=c++20 -Wall -O3 -o build/example_gxsl build/example_gxsl.*.cc
     fi
@@ -46,10 +46,16 @@ case "$1" in
       ./build/example
     fi
     if [[ -f build/example_gxsl ]]; then
+      ./build/example_gxsl
       cd build/
-      for f in *.frag; do
-        glslangValidator$EXE $f | sed "s/^ERROR: 0/build\/$f/g" | sed "/\.frag$/d"
+      for f in *.frag *.vert *.comp; do
+        glslangValidator$EXE $f | sed "s/^ERROR: 0/build\/$f/g" | sed "/\.\(frag\|vert\|comp\)$/d"
       done
+      if command -v naga$EXE > /dev/null; then
+        for f in *.wgsl; do
+          naga$EXE $f | sed "s/^/build\/$f: /g"
+        done
+      fi
       cd - > /dev/null
     fi
 